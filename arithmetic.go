@@ -67,11 +67,15 @@ func newDuration(d int, unit uint8) SQLer {
 }
 
 func (d duration) SQL() (string, []interface{}, error) {
+	return SQLDialect(d, Default)
+}
+
+func (d duration) render(r *renderer) (string, []interface{}, error) {
 	unit, ok := durations[d.unit]
 	if !ok {
 		return "", nil, fmt.Errorf("unsupported duration")
 	}
-	return fmt.Sprintf("INTERVAL %d %s", d.wait, unit), nil, nil
+	return r.keyword(r.Interval(d.wait, unit)), nil, nil
 }
 
 const (
@@ -143,14 +147,18 @@ func Modulo(left, right SQLer) SQLer {
 }
 
 func (a arithmetic) SQL() (string, []interface{}, error) {
+	return SQLDialect(a, Default)
+}
+
+func (a arithmetic) render(r *renderer) (string, []interface{}, error) {
 	var args []interface{}
-	left, as, err := a.left.SQL()
+	left, as, err := r.render(a.left)
 	if err != nil {
 		return "", nil, err
 	}
 	args = append(args, as...)
 
-	right, as, err := a.right.SQL()
+	right, as, err := r.render(a.right)
 	if err != nil {
 		return "", nil, err
 	}
@@ -168,3 +176,66 @@ func (a arithmetic) SQL() (string, []interface{}, error) {
 	}
 	return fmt.Sprintf("%s %s %s", left, op, right), args, nil
 }
+
+type negate struct {
+	expr SQLer
+}
+
+// Negate returns the opposite of expr, written -expr.
+func Negate(expr SQLer) SQLer {
+	return negate{
+		expr: expr,
+	}
+}
+
+func (n negate) SQL() (string, []interface{}, error) {
+	return SQLDialect(n, Default)
+}
+
+func (n negate) render(r *renderer) (string, []interface{}, error) {
+	sql, args, err := r.render(n.expr)
+	if err != nil {
+		return "", nil, err
+	}
+	switch n.expr.(type) {
+	case arithmetic, negate, concat:
+		sql = fmt.Sprintf("(%s)", sql)
+	}
+	return "-" + sql, args, nil
+}
+
+type concat struct {
+	left  SQLer
+	right SQLer
+}
+
+// Concat returns the concatenation of the strings left and right. It is
+// written with the CONCAT function for MySQL where || is a logical OR.
+func Concat(left, right SQLer) SQLer {
+	return concat{
+		left:  left,
+		right: right,
+	}
+}
+
+func (c concat) SQL() (string, []interface{}, error) {
+	return SQLDialect(c, Default)
+}
+
+func (c concat) render(r *renderer) (string, []interface{}, error) {
+	var args []interface{}
+	left, as, err := r.render(c.left)
+	if err != nil {
+		return "", nil, err
+	}
+	args = append(args, as...)
+	right, as, err := r.render(c.right)
+	if err != nil {
+		return "", nil, err
+	}
+	args = append(args, as...)
+	if r.is(MySQL) {
+		return fmt.Sprintf("CONCAT(%s, %s)", left, right), args, nil
+	}
+	return fmt.Sprintf("%s || %s", left, right), args, nil
+}
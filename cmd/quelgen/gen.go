@@ -0,0 +1,232 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"strings"
+	"unicode"
+
+	"github.com/midbel/quel"
+)
+
+const header = "// Code generated by quelgen. DO NOT EDIT.\n\n"
+
+// generate returns the Go source declaring for each table:
+//
+//   - the constants holding the names of the table and of its columns
+//   - a struct with a field tagged by db for each column to scan its rows
+//   - a typed definition embedding a quel.Table with a quel.Column field per
+//     column
+//   - the functions creating the statements on the table
+func generate(pkg string, tables []quel.Table) ([]byte, error) {
+	var (
+		body  bytes.Buffer
+		names = make(map[string]string)
+		times bool
+	)
+	for _, t := range tables {
+		g := tableGen{
+			Table: t,
+			name:  goName(t.Name()),
+		}
+		if other, ok := names[g.name]; ok {
+			return nil, fmt.Errorf("tables %s and %s give the same Go name %s", other, t.Name(), g.name)
+		}
+		names[g.name] = t.Name()
+		if err := g.prepare(); err != nil {
+			return nil, err
+		}
+		g.write(&body)
+		times = times || g.times
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString(header)
+	fmt.Fprintf(&buf, "package %s\n\n", pkg)
+	buf.WriteString("import (\n")
+	if times {
+		buf.WriteString("\t\"time\"\n\n")
+	}
+	buf.WriteString("\t\"github.com/midbel/quel\"\n")
+	buf.WriteString(")\n")
+	buf.Write(body.Bytes())
+	return format.Source(buf.Bytes())
+}
+
+type columnGen struct {
+	quel.Column
+	field  string
+	konst  string
+	gotype string
+}
+
+type tableGen struct {
+	quel.Table
+	name    string
+	columns []columnGen
+	times   bool
+}
+
+func (g *tableGen) prepare() error {
+	seen := map[string]bool{
+		g.name + "Table": true,
+		g.name + "Row":   true,
+		g.name + "Def":   true,
+	}
+	for _, c := range g.Columns() {
+		field := goName(c.Name())
+		if field == "Table" {
+			return fmt.Errorf("%s: column %s conflicts with the embedded table", g.Name(), c.Name())
+		}
+		konst := g.name + field
+		if seen[konst] {
+			konst += "Column"
+		}
+		if seen[konst] {
+			return fmt.Errorf("%s: column %s gives a duplicated Go name %s", g.Name(), c.Name(), konst)
+		}
+		seen[konst] = true
+
+		sql, _, err := c.Type().SQL()
+		if err != nil {
+			return fmt.Errorf("%s.%s: %w", g.Name(), c.Name(), err)
+		}
+		kind := goType(sql, c.Nullable())
+		g.times = g.times || strings.Contains(kind, "time.")
+		g.columns = append(g.columns, columnGen{
+			Column: c,
+			field:  field,
+			konst:  konst,
+			gotype: kind,
+		})
+	}
+	return nil
+}
+
+func (g *tableGen) write(w *bytes.Buffer) {
+	var (
+		table = g.name + "Table"
+		row   = g.name + "Row"
+		def   = g.name + "Def"
+	)
+	fmt.Fprintf(w, "\n// Names of the table %s and of its columns.\n", g.Name())
+	w.WriteString("const (\n")
+	fmt.Fprintf(w, "%s = %q\n", table, g.Name())
+	for _, c := range g.columns {
+		fmt.Fprintf(w, "%s = %q\n", c.konst, c.Name())
+	}
+	w.WriteString(")\n\n")
+
+	fmt.Fprintf(w, "// %s is a row of the table %s.\n", row, g.Name())
+	fmt.Fprintf(w, "type %s struct {\n", row)
+	for _, c := range g.columns {
+		fmt.Fprintf(w, "%s %s `db:%q`\n", c.field, c.gotype, c.Name())
+	}
+	w.WriteString("}\n\n")
+
+	fmt.Fprintf(w, "// %s is the typed definition of the table %s.\n", def, g.Name())
+	fmt.Fprintf(w, "type %s struct {\nquel.Table\n", def)
+	for _, c := range g.columns {
+		fmt.Fprintf(w, "%s quel.Column\n", c.field)
+	}
+	w.WriteString("}\n\n")
+
+	fmt.Fprintf(w, "// %s is the definition of the table %s.\n", g.name, g.Name())
+	fmt.Fprintf(w, "var %s = new%s(\"\")\n\n", g.name, def)
+
+	fmt.Fprintf(w, "func new%s(alias string) %s {\n", def, def)
+	fmt.Fprintf(w, "t, err := quel.NewTable(%s,\n", table)
+	for _, c := range g.columns {
+		fmt.Fprintf(w, "quel.TableColumn(%s, %#v", c.konst, c.Type())
+		if !c.Nullable() {
+			w.WriteString(", quel.ColumnNotNull()")
+		}
+		w.WriteString("),\n")
+	}
+	w.WriteString(")\nif err != nil {\npanic(err)\n}\n")
+	w.WriteString("if alias != \"\" {\nt = t.As(alias)\n}\n")
+	fmt.Fprintf(w, "return %s{\nTable: t,\n", def)
+	for _, c := range g.columns {
+		fmt.Fprintf(w, "%s: t.Column(%s),\n", c.field, c.konst)
+	}
+	w.WriteString("}\n}\n\n")
+
+	fmt.Fprintf(w, "// As returns the definition of the table %s aliased with alias.\n", g.Name())
+	fmt.Fprintf(w, "func (%s) As(alias string) %s {\nreturn new%s(alias)\n}\n", def, def, def)
+
+	for _, s := range []string{"Select", "Insert", "Update", "Delete"} {
+		fmt.Fprintf(w, "\n// %s%s returns a new %s statement on the table %s.\n", s, g.name, strings.ToUpper(s), g.Name())
+		fmt.Fprintf(w, "func %s%s(options ...quel.%sOption) (quel.%s, error) {\n", s, g.name, s, s)
+		fmt.Fprintf(w, "return quel.New%s(%s, options...)\n}\n", s, table)
+	}
+}
+
+// goType returns the Go type of the values of a column of the given SQL type.
+// The rules used to find the type are close to the ones used by SQLite to
+// find the affinity of a column. Nullable columns are given a pointer type.
+func goType(sql string, nullable bool) string {
+	var (
+		str = strings.ToUpper(sql)
+		typ string
+	)
+	switch {
+	case strings.Contains(str, "INTERVAL"):
+		typ = "string"
+	case strings.Contains(str, "INT"):
+		typ = "int64"
+	case strings.Contains(str, "BOOL"):
+		typ = "bool"
+	case strings.Contains(str, "JSON"), strings.Contains(str, "BLOB"), strings.Contains(str, "BYTEA"):
+		return "[]byte"
+	case strings.Contains(str, "CHAR"), strings.Contains(str, "CLOB"), strings.Contains(str, "TEXT"):
+		typ = "string"
+	case strings.Contains(str, "REAL"), strings.Contains(str, "FLOA"), strings.Contains(str, "DOUB"),
+		strings.Contains(str, "DECIMAL"), strings.Contains(str, "NUMERIC"):
+		typ = "float64"
+	case strings.Contains(str, "DATE"), strings.Contains(str, "TIME"):
+		typ = "time.Time"
+	default:
+		return "interface{}"
+	}
+	if nullable {
+		typ = "*" + typ
+	}
+	return typ
+}
+
+var initialisms = map[string]string{
+	"api":  "API",
+	"html": "HTML",
+	"http": "HTTP",
+	"id":   "ID",
+	"ip":   "IP",
+	"json": "JSON",
+	"sql":  "SQL",
+	"uid":  "UID",
+	"url":  "URL",
+	"uuid": "UUID",
+}
+
+// goName turns the name of a table or of a column into an exported Go
+// identifier, eg user_id gives UserID.
+func goName(name string) string {
+	var b strings.Builder
+	words := strings.FieldsFunc(name, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	for _, w := range words {
+		if str, ok := initialisms[strings.ToLower(w)]; ok {
+			b.WriteString(str)
+			continue
+		}
+		rs := []rune(w)
+		rs[0] = unicode.ToUpper(rs[0])
+		b.WriteString(string(rs))
+	}
+	str := b.String()
+	if str == "" || !unicode.IsLetter([]rune(str)[0]) {
+		str = "X" + str
+	}
+	return str
+}
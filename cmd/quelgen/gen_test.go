@@ -0,0 +1,69 @@
+package main
+
+import (
+	"go/parser"
+	"go/token"
+	"strings"
+	"testing"
+
+	"github.com/midbel/quel"
+)
+
+func TestGenerate(t *testing.T) {
+	ddl := `
+CREATE TABLE "users" (
+	"id" INTEGER PRIMARY KEY,
+	"email" VARCHAR(255) NOT NULL,
+	"created_at" TIMESTAMP,
+	"settings" JSON
+);
+CREATE TABLE user_roles (user_id INTEGER, role TEXT, PRIMARY KEY (user_id, role));
+CREATE INDEX users_email ON users (email);
+`
+	tables, err := loadTables([]byte(ddl), quel.Postgres)
+	if err != nil {
+		t.Fatalf("error loading tables! %s", err)
+	}
+	code, err := generate("models", tables)
+	if err != nil {
+		t.Fatalf("error generating code! %s", err)
+	}
+	if _, err := parser.ParseFile(token.NewFileSet(), "models.go", code, 0); err != nil {
+		t.Fatalf("invalid code generated! %s", err)
+	}
+	str := string(code)
+	want := []string{
+		"package models",
+		`UsersTable     = "users"`,
+		"CreatedAt *time.Time `db:\"created_at\"`",
+		"Settings  []byte     `db:\"settings\"`",
+		"quel.TableColumn(UsersEmail, quel.TypeVarchar(255), quel.ColumnNotNull()),",
+		"type UserRolesDef struct",
+		"UserID int64  `db:\"user_id\"`",
+		"func SelectUserRoles(options ...quel.SelectOption) (quel.Select, error) {",
+		"func (UsersDef) As(alias string) UsersDef {",
+	}
+	for _, w := range want {
+		if !strings.Contains(str, w) {
+			t.Errorf("generated code does not contain %q", w)
+		}
+	}
+	if _, err := generate("models", append(tables, tables[0])); err == nil {
+		t.Errorf("expected error when tables have the same name")
+	}
+}
+
+func TestGoName(t *testing.T) {
+	data := map[string]string{
+		"users":      "Users",
+		"user_id":    "UserID",
+		"api_url":    "APIURL",
+		"2fa":        "X2fa",
+		"first name": "FirstName",
+	}
+	for in, want := range data {
+		if got := goName(in); got != want {
+			t.Errorf("%s: want %s, got %s", in, want, got)
+		}
+	}
+}
@@ -0,0 +1,125 @@
+// Command quelgen generates the Go definitions of the tables of a schema to
+// build queries with quel without typing the names of tables and columns as
+// strings.
+//
+// Usage:
+//
+//	quelgen [-p package] [-o file] [-d dialect] schema
+//
+// schema is either a file with CREATE TABLE statements or a SQLite database.
+// The other statements of a DDL file are ignored. The dialect is used to read
+// the quoted identifiers of a DDL file and is one of default, ansi, postgres,
+// mysql or sqlite.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/midbel/quel"
+)
+
+func main() {
+	var (
+		pkg     = flag.String("p", "schema", "name of the package of the generated code")
+		out     = flag.String("o", "", "write the generated code to file instead of stdout")
+		dialect = flag.String("d", "default", "dialect of the DDL file")
+	)
+	flag.Usage = func() {
+		fmt.Fprintln(os.Stderr, "usage: quelgen [-p package] [-o file] [-d dialect] schema")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() != 1 {
+		flag.Usage()
+		os.Exit(2)
+	}
+	if err := run(flag.Arg(0), *pkg, *out, *dialect); err != nil {
+		fmt.Fprintln(os.Stderr, "quelgen:", err)
+		os.Exit(1)
+	}
+}
+
+func run(file, pkg, out, dialect string) error {
+	d, err := dialectOf(dialect)
+	if err != nil {
+		return err
+	}
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return err
+	}
+	tables, err := loadTables(data, d)
+	if err != nil {
+		return fmt.Errorf("%s: %w", file, err)
+	}
+	if len(tables) == 0 {
+		return fmt.Errorf("%s: no tables found", file)
+	}
+	code, err := generate(pkg, tables)
+	if err != nil {
+		return err
+	}
+	if out == "" {
+		_, err = os.Stdout.Write(code)
+		return err
+	}
+	return os.WriteFile(out, code, 0644)
+}
+
+// loadTables returns the tables created by data that is either a DDL script
+// or a SQLite database.
+func loadTables(data []byte, d quel.Dialect) ([]quel.Table, error) {
+	var list []quel.SQLer
+	if isSQLite(data) {
+		stmts, err := readSQLite(data)
+		if err != nil {
+			return nil, err
+		}
+		for _, s := range stmts {
+			q, err := quel.Parse(s, quel.SQLite)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", s, err)
+			}
+			list = append(list, q)
+		}
+	} else {
+		stmts, err := quel.ParseScript(string(data), d)
+		if err != nil {
+			return nil, err
+		}
+		list = stmts
+	}
+	var tables []quel.Table
+	for _, s := range list {
+		c, ok := s.(quel.CreateTable)
+		if !ok {
+			continue
+		}
+		t, err := quel.TableOf(c)
+		if err != nil {
+			return nil, err
+		}
+		tables = append(tables, t)
+	}
+	return tables, nil
+}
+
+func dialectOf(name string) (quel.Dialect, error) {
+	switch strings.ToLower(name) {
+	case "", "default":
+		return quel.Default, nil
+	case "ansi":
+		return quel.ANSI, nil
+	case "postgres":
+		return quel.Postgres, nil
+	case "mysql":
+		return quel.MySQL, nil
+	case "sqlite":
+		return quel.SQLite, nil
+	default:
+		return nil, fmt.Errorf("unknown dialect %s", name)
+	}
+}
@@ -0,0 +1,297 @@
+package main
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+)
+
+// The functions of this file read the schema of a SQLite database without
+// any driver by decoding its file. The first page of the file holds the root
+// of the b-tree of the table sqlite_schema whose rows give, among others, the
+// CREATE TABLE statement of each table.
+//
+// See https://www.sqlite.org/fileformat2.html for the description of the
+// format.
+
+const sqliteMagic = "SQLite format 3\x00"
+
+const (
+	pageInterior = 0x05
+	pageLeaf     = 0x0d
+)
+
+// maxDepth is the depth of b-tree after which the file is considered to be
+// corrupted.
+const maxDepth = 64
+
+var errCorrupted = errors.New("sqlite: corrupted file")
+
+func isSQLite(data []byte) bool {
+	return bytes.HasPrefix(data, []byte(sqliteMagic))
+}
+
+// readSQLite returns the sql of the tables created in the database stored in
+// data. The internal tables of SQLite are skipped.
+func readSQLite(data []byte) ([]string, error) {
+	db, err := openSQLite(data)
+	if err != nil {
+		return nil, err
+	}
+	var list []string
+	err = db.walk(1, 0, func(payload []byte) error {
+		values, err := decodeRecord(payload)
+		if err != nil {
+			return err
+		}
+		if len(values) < 5 {
+			return fmt.Errorf("%w: invalid schema row", errCorrupted)
+		}
+		kind, _ := values[0].(string)
+		name, _ := values[1].(string)
+		sql, _ := values[4].(string)
+		if kind != "table" || sql == "" || strings.HasPrefix(name, "sqlite_") {
+			return nil
+		}
+		list = append(list, sql)
+		return nil
+	})
+	return list, err
+}
+
+type sqliteFile struct {
+	data   []byte
+	size   int
+	usable int
+}
+
+func openSQLite(data []byte) (*sqliteFile, error) {
+	if len(data) < 100 || !isSQLite(data) {
+		return nil, fmt.Errorf("sqlite: not a database file")
+	}
+	size := int(binary.BigEndian.Uint16(data[16:]))
+	if size == 1 {
+		size = 65536
+	}
+	if size < 512 || size&(size-1) != 0 {
+		return nil, fmt.Errorf("%w: invalid page size %d", errCorrupted, size)
+	}
+	if enc := binary.BigEndian.Uint32(data[56:]); enc > 1 {
+		return nil, fmt.Errorf("sqlite: only UTF-8 databases are supported")
+	}
+	db := sqliteFile{
+		data:   data,
+		size:   size,
+		usable: size - int(data[20]),
+	}
+	return &db, nil
+}
+
+// page returns the content of the page n. Pages are numbered from 1.
+func (f *sqliteFile) page(n int) ([]byte, error) {
+	offset := (n - 1) * f.size
+	if n < 1 || offset+f.size > len(f.data) {
+		return nil, fmt.Errorf("%w: page %d out of range", errCorrupted, n)
+	}
+	return f.data[offset : offset+f.size], nil
+}
+
+// walk calls fn with the payload of each row of the table b-tree whose root
+// is the page n.
+func (f *sqliteFile) walk(n, depth int, fn func([]byte) error) error {
+	if depth > maxDepth {
+		return fmt.Errorf("%w: b-tree too deep", errCorrupted)
+	}
+	page, err := f.page(n)
+	if err != nil {
+		return err
+	}
+	var offset int
+	if n == 1 {
+		offset = 100
+	}
+	var (
+		kind  = page[offset]
+		count = int(binary.BigEndian.Uint16(page[offset+3:]))
+		cells = offset + 8
+	)
+	if kind == pageInterior {
+		cells += 4
+	}
+	if cells+2*count > len(page) {
+		return fmt.Errorf("%w: too many cells in page %d", errCorrupted, n)
+	}
+	for i := 0; i < count; i++ {
+		cell := int(binary.BigEndian.Uint16(page[cells+2*i:]))
+		if cell+4 > len(page) {
+			return fmt.Errorf("%w: invalid cell in page %d", errCorrupted, n)
+		}
+		switch kind {
+		case pageLeaf:
+			payload, err := f.payload(page, cell)
+			if err != nil {
+				return err
+			}
+			if err := fn(payload); err != nil {
+				return err
+			}
+		case pageInterior:
+			child := int(binary.BigEndian.Uint32(page[cell:]))
+			if err := f.walk(child, depth+1, fn); err != nil {
+				return err
+			}
+		default:
+			return fmt.Errorf("%w: unexpected page type %#x", errCorrupted, kind)
+		}
+	}
+	if kind == pageInterior {
+		right := int(binary.BigEndian.Uint32(page[offset+8:]))
+		return f.walk(right, depth+1, fn)
+	}
+	return nil
+}
+
+// payload returns the payload of the cell of a leaf page starting at offset.
+// The part of the payload that does not fit in the page is read from its
+// overflow pages.
+func (f *sqliteFile) payload(page []byte, offset int) ([]byte, error) {
+	size, n := varint(page[offset:])
+	if n == 0 || size < 0 {
+		return nil, errCorrupted
+	}
+	offset += n
+	if _, n = varint(page[offset:]); n == 0 {
+		return nil, errCorrupted
+	}
+	offset += n
+
+	local := f.localSize(int(size))
+	if offset+local > len(page) {
+		return nil, fmt.Errorf("%w: payload out of page", errCorrupted)
+	}
+	payload := append([]byte{}, page[offset:offset+local]...)
+	if local == int(size) {
+		return payload, nil
+	}
+	if offset+local+4 > len(page) {
+		return nil, fmt.Errorf("%w: payload out of page", errCorrupted)
+	}
+	next := int(binary.BigEndian.Uint32(page[offset+local:]))
+	for i := 0; len(payload) < int(size); i++ {
+		if next == 0 || i > len(f.data)/f.size {
+			return nil, fmt.Errorf("%w: truncated payload", errCorrupted)
+		}
+		p, err := f.page(next)
+		if err != nil {
+			return nil, err
+		}
+		next = int(binary.BigEndian.Uint32(p))
+		rest := int(size) - len(payload)
+		if rest > f.usable-4 {
+			rest = f.usable - 4
+		}
+		payload = append(payload, p[4:4+rest]...)
+	}
+	return payload, nil
+}
+
+// localSize returns the number of bytes of a payload of size bytes that are
+// stored in a leaf page of a table b-tree.
+func (f *sqliteFile) localSize(size int) int {
+	var (
+		u = f.usable
+		x = u - 35
+	)
+	if size <= x {
+		return size
+	}
+	m := ((u-12)*32)/255 - 23
+	k := m + (size-m)%(u-4)
+	if k <= x {
+		return k
+	}
+	return m
+}
+
+// decodeRecord returns the values of a record. Integers are returned as
+// int64, floats as float64, texts as string and blobs as []byte.
+func decodeRecord(data []byte) ([]interface{}, error) {
+	size, n := varint(data)
+	if n == 0 || size < int64(n) || size > int64(len(data)) {
+		return nil, fmt.Errorf("%w: invalid record header", errCorrupted)
+	}
+	var kinds []int64
+	for offset := n; offset < int(size); {
+		k, n := varint(data[offset:size])
+		if n == 0 {
+			return nil, fmt.Errorf("%w: invalid record header", errCorrupted)
+		}
+		kinds = append(kinds, k)
+		offset += n
+	}
+	var (
+		body   = data[size:]
+		values []interface{}
+	)
+	for _, k := range kinds {
+		var (
+			value interface{}
+			width int
+		)
+		switch {
+		case k == 0:
+		case k >= 1 && k <= 6:
+			width = []int{1, 2, 3, 4, 6, 8}[k-1]
+		case k == 7:
+			width = 8
+		case k == 8:
+			value = int64(0)
+		case k == 9:
+			value = int64(1)
+		case k >= 12:
+			width = int((k - 12) / 2)
+		default:
+			return nil, fmt.Errorf("%w: invalid serial type %d", errCorrupted, k)
+		}
+		if width > len(body) {
+			return nil, fmt.Errorf("%w: truncated record", errCorrupted)
+		}
+		switch buf := body[:width]; {
+		case k >= 1 && k <= 6:
+			var v int64
+			for _, b := range buf {
+				v = v<<8 | int64(b)
+			}
+			shift := 64 - 8*uint(width)
+			value = (v << shift) >> shift
+		case k == 7:
+			value = math.Float64frombits(binary.BigEndian.Uint64(buf))
+		case k >= 12 && k%2 == 0:
+			value = append([]byte{}, buf...)
+		case k >= 13:
+			value = string(buf)
+		}
+		values = append(values, value)
+		body = body[width:]
+	}
+	return values, nil
+}
+
+// varint decodes the variable length integer at the start of data. It
+// returns the number of bytes read or 0 if data is too short.
+func varint(data []byte) (int64, int) {
+	var v uint64
+	for i := 0; i < 9 && i < len(data); i++ {
+		if i == 8 {
+			return int64(v<<8 | uint64(data[i])), 9
+		}
+		v = v<<7 | uint64(data[i]&0x7f)
+		if data[i]&0x80 == 0 {
+			return int64(v), i + 1
+		}
+	}
+	return 0, 0
+}
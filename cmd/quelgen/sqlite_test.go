@@ -0,0 +1,61 @@
+package main
+
+import (
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestReadSQLite(t *testing.T) {
+	data, err := os.ReadFile("testdata/schema.db")
+	if err != nil {
+		t.Fatalf("error reading database! %s", err)
+	}
+	list, err := readSQLite(data)
+	if err != nil {
+		t.Fatalf("error reading schema! %s", err)
+	}
+	if len(list) != 27 {
+		t.Fatalf("tables count mismatched! want 27, got %d", len(list))
+	}
+	if !strings.HasPrefix(list[0], "CREATE TABLE users") {
+		t.Errorf("unexpected first table: %s", list[0])
+	}
+	if !strings.HasSuffix(list[1], "column_with_a_long_name_29 VARCHAR(64) NOT NULL DEFAULT 'value 29')") {
+		t.Errorf("overflowing sql not fully read: %s", list[1])
+	}
+	tables, err := loadTables(data, nil)
+	if err != nil {
+		t.Fatalf("error loading tables! %s", err)
+	}
+	if len(tables) != len(list) {
+		t.Errorf("tables count mismatched! want %d, got %d", len(list), len(tables))
+	}
+
+	if _, err := readSQLite(data[:1024]); err == nil {
+		t.Errorf("expected error when reading truncated database")
+	}
+	if _, err := readSQLite([]byte("CREATE TABLE users (id INTEGER)")); err == nil {
+		t.Errorf("expected error when reading invalid database")
+	}
+}
+
+func TestVarint(t *testing.T) {
+	data := []struct {
+		Input []byte
+		Want  int64
+		Size  int
+	}{
+		{Input: []byte{0x7f}, Want: 127, Size: 1},
+		{Input: []byte{0x81, 0x00}, Want: 128, Size: 2},
+		{Input: []byte{0x82, 0xac, 0x02}, Want: 38402, Size: 3},
+		{Input: []byte{0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff}, Want: -1, Size: 9},
+		{Input: []byte{0x81}, Want: 0, Size: 0},
+	}
+	for _, d := range data {
+		got, n := varint(d.Input)
+		if got != d.Want || n != d.Size {
+			t.Errorf("%x: want %d (%d bytes), got %d (%d bytes)", d.Input, d.Want, d.Size, got, n)
+		}
+	}
+}
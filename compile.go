@@ -0,0 +1,100 @@
+package quel
+
+import (
+	"fmt"
+)
+
+// Template is a query rendered once and whose arguments are given when it is
+// executed. A Template is immutable and can be shared between goroutines.
+type Template struct {
+	query  string
+	params []string
+}
+
+// Compile renders q with the given dialect into a Template. Every argument of
+// q should have a valid name: the values given when building q are discarded
+// and replaced by the values given to Bind or BindStruct.
+func Compile(q SQLer, d Dialect) (Template, error) {
+	if d == nil {
+		d = Default
+	}
+	r := renderer{
+		Dialect: d,
+		compile: true,
+	}
+	str, _, err := r.renderNamed(q)
+	if err != nil {
+		return Template{}, err
+	}
+	if r.count != len(r.params) {
+		return Template{}, fmt.Errorf("%w: query has unnamed arguments", ErrArg)
+	}
+	t := Template{
+		query:  str,
+		params: r.params,
+	}
+	return t, nil
+}
+
+// Query returns the SQL of the template.
+func (t Template) Query() string {
+	return t.query
+}
+
+// Params returns the names of the parameters of the template in the order
+// their values are returned by Bind.
+func (t Template) Params() []string {
+	return append([]string{}, t.params...)
+}
+
+// Bind returns the arguments of the template with their values taken from
+// values. It is an error if a parameter has no value or if values contains
+// a name that is not a parameter of the template.
+func (t Template) Bind(values map[string]interface{}) ([]interface{}, error) {
+	args := make([]interface{}, len(t.params))
+	for i, p := range t.params {
+		v, ok := values[p]
+		if !ok {
+			return nil, fmt.Errorf("%w: missing value for %q", ErrArg, p)
+		}
+		args[i] = v
+	}
+	for k := range values {
+		if !t.has(k) {
+			return nil, fmt.Errorf("%w: unknown parameter %q", ErrArg, k)
+		}
+	}
+	return args, nil
+}
+
+// BindStruct is like Bind but takes the values of the parameters from the
+// fields of v. The fields are matched by the name given in their db tag or by
+// their name in lower case. Fields that are not parameters of the template are
+// ignored.
+func (t Template) BindStruct(v interface{}) ([]interface{}, error) {
+	val, err := structValue(v)
+	if err != nil {
+		return nil, err
+	}
+	values := make(map[string]interface{})
+	for _, f := range structFields(val.Type()) {
+		if !t.has(f.name) {
+			continue
+		}
+		fv, ok := fieldByIndex(val, f.index)
+		if !ok {
+			continue
+		}
+		values[f.name] = fv.Interface()
+	}
+	return t.Bind(values)
+}
+
+func (t Template) has(name string) bool {
+	for _, p := range t.params {
+		if p == name {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,86 @@
+package quel
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestCompile(t *testing.T) {
+	where := And(Equal(NewIdent("role"), Arg("role", nil)), Or(Equal(NewIdent("first"), Arg("name", nil)), Equal(NewIdent("last"), Arg("name", nil))))
+	q, err := NewSelect("users", SelectColumns("id"), SelectWhere(where))
+	if err != nil {
+		t.Fatalf("error creating query! %s", err)
+	}
+	data := []struct {
+		Dialect Dialect
+		Query   string
+		Params  []string
+		Args    []interface{}
+	}{
+		{
+			Dialect: Default,
+			Query:   "SELECT id FROM users WHERE role = ? AND (first = ? OR last = ?)",
+			Params:  []string{"role", "name", "name"},
+			Args:    []interface{}{"admin", "roger", "roger"},
+		},
+		{
+			Dialect: Postgres,
+			Query:   `SELECT "id" FROM "users" WHERE "role" = $1 AND ("first" = $2 OR "last" = $2)`,
+			Params:  []string{"role", "name"},
+			Args:    []interface{}{"admin", "roger"},
+		},
+	}
+	for _, d := range data {
+		tpl, err := Compile(q, d.Dialect)
+		if err != nil {
+			t.Errorf("error compiling query! %s", err)
+			continue
+		}
+		if got := tpl.Query(); got != d.Query {
+			t.Errorf("queries mismatched!")
+			t.Logf("\twant: %s", d.Query)
+			t.Logf("\tgot:  %s", got)
+		}
+		if got := tpl.Params(); !reflect.DeepEqual(got, d.Params) {
+			t.Errorf("params mismatched! want %v, got %v", d.Params, got)
+		}
+		args, err := tpl.Bind(map[string]interface{}{"role": "admin", "name": "roger"})
+		if err != nil {
+			t.Errorf("error binding values! %s", err)
+			continue
+		}
+		if !reflect.DeepEqual(args, d.Args) {
+			t.Errorf("arguments mismatched! want %v, got %v", d.Args, args)
+		}
+		row := struct {
+			Role string
+			Name string `db:"name"`
+			Age  int
+		}{
+			Role: "admin",
+			Name: "roger",
+		}
+		args, err = tpl.BindStruct(&row)
+		if err != nil {
+			t.Errorf("error binding struct! %s", err)
+			continue
+		}
+		if !reflect.DeepEqual(args, d.Args) {
+			t.Errorf("arguments mismatched! want %v, got %v", d.Args, args)
+		}
+		if _, err := tpl.BindStruct(nil); !errors.Is(err, ErrArg) {
+			t.Errorf("expected ErrArg for nil struct, got %v", err)
+		}
+		if _, err := tpl.Bind(map[string]interface{}{"role": "admin"}); !errors.Is(err, ErrArg) {
+			t.Errorf("expected ErrArg for missing parameter, got %v", err)
+		}
+		if _, err := tpl.Bind(map[string]interface{}{"role": "admin", "name": "roger", "age": 42}); !errors.Is(err, ErrArg) {
+			t.Errorf("expected ErrArg for unknown parameter, got %v", err)
+		}
+	}
+	q, _ = NewSelect("users", SelectWhere(Equal(NewIdent("id"), Arg("", 1))))
+	if _, err := Compile(q, Default); !errors.Is(err, ErrArg) {
+		t.Errorf("expected ErrArg for unnamed argument, got %v", err)
+	}
+}
@@ -0,0 +1,248 @@
+package quel
+
+import (
+	"fmt"
+	"strings"
+)
+
+// InsertOnConflict sets the action to take when a row to insert conflicts with
+// an existing one. target is created with OnColumns or OnConstraint and can be
+// nil. action is created with DoNothing or DoUpdate.
+//
+// The clause is rendered as ON CONFLICT except for MySQL where it is rendered
+// as ON DUPLICATE KEY UPDATE. MySQL has no DO NOTHING: with a target made of
+// columns, the first one is assigned to itself. Without such a target, INSERT
+// IGNORE is used instead but beware that it also turns errors other than
+// duplicate keys, like invalid or truncated values, into warnings. MySQL
+// ignores the target otherwise.
+func InsertOnConflict(target, action SQLer) InsertOption {
+	return func(i *Insert) error {
+		if target != nil {
+			if _, ok := target.(conflictTarget); !ok {
+				return fmt.Errorf("on conflict: %w: invalid target", ErrSyntax)
+			}
+		}
+		a, ok := action.(conflictAction)
+		if !ok {
+			return fmt.Errorf("on conflict: %w: invalid action", ErrSyntax)
+		}
+		if a.err != nil {
+			return a.err
+		}
+		if a.update && target == nil {
+			return fmt.Errorf("on conflict: %w: target required to update", ErrSyntax)
+		}
+		i.conflict = conflict{
+			target: target,
+			action: action,
+		}
+		return nil
+	}
+}
+
+type conflict struct {
+	target SQLer
+	action SQLer
+}
+
+func (c conflict) SQL() (string, []interface{}, error) {
+	return SQLDialect(c, Default)
+}
+
+func (c conflict) render(r *renderer) (string, []interface{}, error) {
+	if r.is(MySQL) {
+		if c.ignore() {
+			return "", nil, nil
+		}
+		if a, ok := c.action.(conflictAction); ok && !a.update {
+			col := c.target.(conflictTarget).columns[0]
+			sql, _, err := r.render(col)
+			if err != nil {
+				return "", nil, err
+			}
+			return fmt.Sprintf("%s %s = %[2]s", r.keyword("ON DUPLICATE KEY UPDATE"), sql), nil, nil
+		}
+		return r.render(c.action)
+	}
+	var b strings.Builder
+	b.WriteString(r.keyword("ON CONFLICT"))
+	if c.target != nil {
+		sql, _, err := r.render(c.target)
+		if err != nil {
+			return "", nil, err
+		}
+		b.WriteString(" ")
+		b.WriteString(sql)
+	}
+	sql, args, err := r.render(c.action)
+	if err != nil {
+		return "", nil, err
+	}
+	b.WriteString(" ")
+	b.WriteString(sql)
+	return b.String(), args, nil
+}
+
+// ignore reports whether c is rendered by MySQL as INSERT IGNORE, that is
+// when nothing has to be done and no column is given as target.
+func (c conflict) ignore() bool {
+	if a, ok := c.action.(conflictAction); !ok || a.update {
+		return false
+	}
+	t, ok := c.target.(conflictTarget)
+	return !ok || len(t.columns) == 0
+}
+
+type conflictTarget struct {
+	columns    []SQLer
+	constraint string
+}
+
+// OnColumns returns the target of InsertOnConflict made of the columns of a
+// unique index.
+func OnColumns(columns ...string) SQLer {
+	var t conflictTarget
+	for _, c := range columns {
+		t.columns = append(t.columns, NewIdent(c))
+	}
+	return t
+}
+
+// OnConstraint returns the target of InsertOnConflict referring to a
+// constraint by its name. It is only supported by Postgres.
+func OnConstraint(name string) SQLer {
+	return conflictTarget{
+		constraint: name,
+	}
+}
+
+func (t conflictTarget) SQL() (string, []interface{}, error) {
+	return SQLDialect(t, Default)
+}
+
+func (t conflictTarget) render(r *renderer) (string, []interface{}, error) {
+	if t.constraint != "" {
+		if r.is(SQLite) {
+			return "", nil, fmt.Errorf("on conflict: constraint not supported by SQLite")
+		}
+		if !isValidIdentifier(t.constraint) {
+			return "", nil, fmt.Errorf("on conflict: %w %q", ErrIdent, t.constraint)
+		}
+		return fmt.Sprintf("%s %s", r.keyword("ON CONSTRAINT"), r.ident(t.constraint)), nil, nil
+	}
+	if len(t.columns) == 0 {
+		return "", nil, fmt.Errorf("on conflict: %w: no columns given", ErrSyntax)
+	}
+	var b strings.Builder
+	b.WriteString("(")
+	as, err := writeSQL(r, &b, t.columns...)
+	if err != nil {
+		return "", nil, err
+	}
+	b.WriteString(")")
+	return b.String(), as, nil
+}
+
+type conflictAction struct {
+	update bool
+	set    []SQLer
+	where  SQLer
+	err    error
+}
+
+// DoNothing returns the action of InsertOnConflict that skips the conflicting
+// rows.
+func DoNothing() SQLer {
+	return conflictAction{}
+}
+
+// DoUpdate returns the action of InsertOnConflict that updates the existing
+// rows. Only the options UpdateColumn, UpdateStruct, UpdateMap and UpdateWhere
+// can be given. The values of the row that was proposed for insertion are
+// available with Excluded.
+func DoUpdate(options ...UpdateOption) SQLer {
+	var (
+		u Update
+		a = conflictAction{update: true}
+	)
+	for _, opt := range options {
+		if a.err = opt(&u); a.err != nil {
+			return a
+		}
+	}
+	switch {
+	case len(u.columns) == 0:
+		a.err = fmt.Errorf("do update: %w: no columns to update", ErrSyntax)
+	case u.table != nil || len(u.returning) > 0 || len(u.sources) > 0 || len(u.ctes) > 0:
+		a.err = fmt.Errorf("do update: %w: unsupported option", ErrSyntax)
+	}
+	a.set, a.where = u.columns, u.where
+	return a
+}
+
+func (a conflictAction) SQL() (string, []interface{}, error) {
+	return SQLDialect(a, Default)
+}
+
+func (a conflictAction) render(r *renderer) (string, []interface{}, error) {
+	if a.err != nil {
+		return "", nil, a.err
+	}
+	if !a.update {
+		if r.is(MySQL) {
+			return "", nil, nil
+		}
+		return r.keyword("DO NOTHING"), nil, nil
+	}
+	var b strings.Builder
+	if r.is(MySQL) {
+		if a.where != nil {
+			return "", nil, fmt.Errorf("do update: where not supported by MySQL")
+		}
+		b.WriteString(r.keyword("ON DUPLICATE KEY UPDATE") + " ")
+	} else {
+		b.WriteString(r.keyword("DO UPDATE SET") + " ")
+	}
+	args, err := writeSQL(r, &b, a.set...)
+	if err != nil {
+		return "", nil, err
+	}
+	if a.where != nil {
+		sql, as, err := r.render(a.where)
+		if err != nil {
+			return "", nil, err
+		}
+		args = append(args, as...)
+		b.WriteString(" " + r.keyword("WHERE") + " ")
+		b.WriteString(sql)
+	}
+	return b.String(), args, nil
+}
+
+type excluded struct {
+	column string
+}
+
+// Excluded refers to the value of column in the row proposed for insertion
+// when used in DoUpdate. It is rendered as EXCLUDED.column or as
+// VALUES(column) for MySQL. VALUES is deprecated since MySQL 8.0.20 but it
+// is still the only syntax known by older versions and by MariaDB.
+func Excluded(column string) SQLer {
+	return excluded{
+		column: column,
+	}
+}
+
+func (e excluded) SQL() (string, []interface{}, error) {
+	return SQLDialect(e, Default)
+}
+
+func (e excluded) render(r *renderer) (string, []interface{}, error) {
+	if !isValidIdentifier(e.column) {
+		return "", nil, fmt.Errorf("excluded: %w %q", ErrIdent, e.column)
+	}
+	if r.is(MySQL) {
+		return fmt.Sprintf("%s(%s)", r.keyword("VALUES"), r.ident(e.column)), nil, nil
+	}
+	return fmt.Sprintf("%s.%s", r.keyword("EXCLUDED"), r.ident(e.column)), nil, nil
+}
@@ -0,0 +1,981 @@
+package quel
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	typeInt uint8 = iota
+	typeSmallInt
+	typeBigInt
+	typeText
+	typeVarchar
+	typeChar
+	typeBool
+	typeDecimal
+	typeReal
+	typeDouble
+	typeDate
+	typeTime
+	typeTimestamp
+	typeBlob
+	typeJSON
+	typeUUID
+	typeInterval
+	typeRaw
+)
+
+type columnType struct {
+	kind uint8
+	args []int
+	name string
+}
+
+func TypeInt() SQLer       { return columnType{kind: typeInt} }
+func TypeSmallInt() SQLer  { return columnType{kind: typeSmallInt} }
+func TypeBigInt() SQLer    { return columnType{kind: typeBigInt} }
+func TypeText() SQLer      { return columnType{kind: typeText} }
+func TypeBool() SQLer      { return columnType{kind: typeBool} }
+func TypeReal() SQLer      { return columnType{kind: typeReal} }
+func TypeDouble() SQLer    { return columnType{kind: typeDouble} }
+func TypeDate() SQLer      { return columnType{kind: typeDate} }
+func TypeTime() SQLer      { return columnType{kind: typeTime} }
+func TypeTimestamp() SQLer { return columnType{kind: typeTimestamp} }
+func TypeBlob() SQLer      { return columnType{kind: typeBlob} }
+func TypeJSON() SQLer      { return columnType{kind: typeJSON} }
+func TypeUUID() SQLer      { return columnType{kind: typeUUID} }
+func TypeInterval() SQLer  { return columnType{kind: typeInterval} }
+
+func TypeVarchar(length int) SQLer {
+	return columnType{kind: typeVarchar, args: []int{length}}
+}
+
+func TypeChar(length int) SQLer {
+	return columnType{kind: typeChar, args: []int{length}}
+}
+
+func TypeDecimal(precision, scale int) SQLer {
+	return columnType{kind: typeDecimal, args: []int{precision, scale}}
+}
+
+// TypeRaw returns a type written as is, for the types that are specific to an
+// engine.
+func TypeRaw(name string) SQLer {
+	return columnType{kind: typeRaw, name: name}
+}
+
+func (t columnType) SQL() (string, []interface{}, error) {
+	return SQLDialect(t, Default)
+}
+
+func (t columnType) render(r *renderer) (string, []interface{}, error) {
+	for i, a := range t.args {
+		if a < 0 || (a == 0 && i == 0) {
+			return "", nil, fmt.Errorf("type: %w: %d", ErrLimit, a)
+		}
+	}
+	var str string
+	switch t.kind {
+	case typeInt:
+		str = "INTEGER"
+		if r.is(MySQL) {
+			str = "INT"
+		}
+	case typeSmallInt:
+		str = "SMALLINT"
+	case typeBigInt:
+		str = "BIGINT"
+	case typeText:
+		str = "TEXT"
+	case typeVarchar:
+		str = fmt.Sprintf("VARCHAR(%d)", t.args[0])
+	case typeChar:
+		str = fmt.Sprintf("CHAR(%d)", t.args[0])
+	case typeBool:
+		str = "BOOLEAN"
+	case typeDecimal:
+		str = fmt.Sprintf("DECIMAL(%d, %d)", t.args[0], t.args[1])
+	case typeReal:
+		str = "REAL"
+	case typeDouble:
+		switch {
+		case r.is(MySQL):
+			str = "DOUBLE"
+		case r.is(SQLite):
+			str = "REAL"
+		default:
+			str = "DOUBLE PRECISION"
+		}
+	case typeDate:
+		str = "DATE"
+	case typeTime:
+		str = "TIME"
+	case typeTimestamp:
+		str = "TIMESTAMP"
+	case typeBlob:
+		str = "BLOB"
+		if r.is(Postgres) {
+			str = "BYTEA"
+		}
+	case typeJSON:
+		str = "JSON"
+		if r.is(SQLite) {
+			str = "TEXT"
+		}
+	case typeUUID:
+		switch {
+		case r.is(Postgres):
+			str = "UUID"
+		case r.is(SQLite):
+			str = "TEXT"
+		default:
+			str = "CHAR(36)"
+		}
+	case typeInterval:
+		str = "INTERVAL"
+		if r.is(SQLite) {
+			str = "TEXT"
+		}
+	case typeRaw:
+		if t.name == "" {
+			return "", nil, fmt.Errorf("type: %w: empty name", ErrSyntax)
+		}
+		return t.name, nil, nil
+	default:
+		return "", nil, fmt.Errorf("unsupported type")
+	}
+	return r.keyword(str), nil, nil
+}
+
+// GoString returns the Go expression creating t. It is used when generating
+// code from a schema.
+func (t columnType) GoString() string {
+	var str string
+	switch t.kind {
+	case typeInt:
+		str = "TypeInt()"
+	case typeSmallInt:
+		str = "TypeSmallInt()"
+	case typeBigInt:
+		str = "TypeBigInt()"
+	case typeText:
+		str = "TypeText()"
+	case typeVarchar:
+		str = fmt.Sprintf("TypeVarchar(%d)", t.args[0])
+	case typeChar:
+		str = fmt.Sprintf("TypeChar(%d)", t.args[0])
+	case typeBool:
+		str = "TypeBool()"
+	case typeDecimal:
+		str = fmt.Sprintf("TypeDecimal(%d, %d)", t.args[0], t.args[1])
+	case typeReal:
+		str = "TypeReal()"
+	case typeDouble:
+		str = "TypeDouble()"
+	case typeDate:
+		str = "TypeDate()"
+	case typeTime:
+		str = "TypeTime()"
+	case typeTimestamp:
+		str = "TypeTimestamp()"
+	case typeBlob:
+		str = "TypeBlob()"
+	case typeJSON:
+		str = "TypeJSON()"
+	case typeUUID:
+		str = "TypeUUID()"
+	case typeInterval:
+		str = "TypeInterval()"
+	default:
+		str = fmt.Sprintf("TypeRaw(%q)", t.name)
+	}
+	return "quel." + str
+}
+
+// Actions of a foreign key when the referenced row is deleted or updated.
+const (
+	Cascade    = "CASCADE"
+	Restrict   = "RESTRICT"
+	SetNull    = "SET NULL"
+	SetDefault = "SET DEFAULT"
+	NoAction   = "NO ACTION"
+)
+
+type ForeignKeyOption func(*foreignKey) error
+
+func OnDelete(action string) ForeignKeyOption {
+	return func(f *foreignKey) error {
+		if !isRefAction(action) {
+			return fmt.Errorf("on delete: %w: unknown action %q", ErrSyntax, action)
+		}
+		f.ondelete = action
+		return nil
+	}
+}
+
+func OnUpdate(action string) ForeignKeyOption {
+	return func(f *foreignKey) error {
+		if !isRefAction(action) {
+			return fmt.Errorf("on update: %w: unknown action %q", ErrSyntax, action)
+		}
+		f.onupdate = action
+		return nil
+	}
+}
+
+func isRefAction(action string) bool {
+	switch action {
+	case Cascade, Restrict, SetNull, SetDefault, NoAction:
+		return true
+	default:
+		return false
+	}
+}
+
+const (
+	constraintPrimary uint8 = iota
+	constraintUnique
+	constraintCheck
+	constraintForeign
+)
+
+type constraint struct {
+	name    string
+	kind    uint8
+	columns []string
+	check   SQLer
+	ref     foreignKey
+	err     error
+}
+
+type foreignKey struct {
+	table    string
+	columns  []string
+	ondelete string
+	onupdate string
+}
+
+// PrimaryKey returns a table constraint to be given to CreateConstraint or
+// AlterAddConstraint.
+func PrimaryKey(columns ...string) SQLer {
+	return newConstraint(constraintPrimary, columns)
+}
+
+func Unique(columns ...string) SQLer {
+	return newConstraint(constraintUnique, columns)
+}
+
+// Check returns a table constraint verifying that cdt, a relational
+// expression, is true for each row.
+func Check(cdt SQLer) SQLer {
+	c := constraint{
+		kind:  constraintCheck,
+		check: cdt,
+	}
+	if !acceptRelational(cdt) {
+		c.err = fmt.Errorf("check: %w", ErrSyntax)
+	}
+	return c
+}
+
+// ForeignKey returns a table constraint making columns refer to the columns
+// refs of table. refs can be empty to refer to the primary key of table.
+func ForeignKey(columns []string, table string, refs []string, options ...ForeignKeyOption) SQLer {
+	c := newConstraint(constraintForeign, columns)
+	ref, err := newForeignKey(table, refs, options)
+	if c.ref = ref; c.err == nil {
+		c.err = err
+	}
+	if c.err == nil && len(refs) > 0 && len(refs) != len(columns) {
+		c.err = fmt.Errorf("foreign key: %w: columns mismatched referenced columns", ErrColumn)
+	}
+	return c
+}
+
+// Constraint gives a name to a table constraint.
+func Constraint(name string, c SQLer) SQLer {
+	x, ok := c.(constraint)
+	if !ok {
+		return constraint{err: fmt.Errorf("constraint: %w: invalid constraint", ErrSyntax)}
+	}
+	if !isValidIdentifier(name) {
+		x.err = fmt.Errorf("constraint: %w %q", ErrIdent, name)
+	}
+	x.name = name
+	return x
+}
+
+func newConstraint(kind uint8, columns []string) constraint {
+	c := constraint{
+		kind:    kind,
+		columns: append([]string{}, columns...),
+	}
+	if len(columns) == 0 {
+		c.err = fmt.Errorf("constraint: %w: no columns given", ErrSyntax)
+	}
+	for _, col := range columns {
+		if !isValidIdentifier(col) {
+			c.err = fmt.Errorf("constraint: %w %q", ErrIdent, col)
+		}
+	}
+	return c
+}
+
+func newForeignKey(table string, columns []string, options []ForeignKeyOption) (foreignKey, error) {
+	f := foreignKey{
+		table:   table,
+		columns: append([]string{}, columns...),
+	}
+	if !isValidIdentifier(table) {
+		return f, fmt.Errorf("references: %w %q", ErrIdent, table)
+	}
+	for _, c := range columns {
+		if !isValidIdentifier(c) {
+			return f, fmt.Errorf("references: %w %q", ErrIdent, c)
+		}
+	}
+	for _, opt := range options {
+		if err := opt(&f); err != nil {
+			return f, err
+		}
+	}
+	return f, nil
+}
+
+func (c constraint) SQL() (string, []interface{}, error) {
+	return SQLDialect(c, Default)
+}
+
+func (c constraint) render(r *renderer) (string, []interface{}, error) {
+	if c.err != nil {
+		return "", nil, c.err
+	}
+	var b strings.Builder
+	if c.name != "" {
+		b.WriteString(r.keyword("CONSTRAINT") + " ")
+		b.WriteString(r.ident(c.name))
+		b.WriteString(" ")
+	}
+	switch c.kind {
+	case constraintPrimary:
+		b.WriteString(r.keyword("PRIMARY KEY") + " ")
+		b.WriteString(r.idents(c.columns))
+	case constraintUnique:
+		b.WriteString(r.keyword("UNIQUE") + " ")
+		b.WriteString(r.idents(c.columns))
+	case constraintForeign:
+		b.WriteString(r.keyword("FOREIGN KEY") + " ")
+		b.WriteString(r.idents(c.columns))
+		b.WriteString(" ")
+		b.WriteString(c.ref.render(r))
+	case constraintCheck:
+		sql, err := renderDDL(r, c.check)
+		if err != nil {
+			return "", nil, err
+		}
+		b.WriteString(r.keyword("CHECK") + " (")
+		b.WriteString(sql)
+		b.WriteString(")")
+	default:
+		return "", nil, fmt.Errorf("unsupported constraint")
+	}
+	return b.String(), nil, nil
+}
+
+func (f foreignKey) render(r *renderer) string {
+	var b strings.Builder
+	b.WriteString(r.keyword("REFERENCES") + " ")
+	b.WriteString(r.ident(f.table))
+	if len(f.columns) > 0 {
+		b.WriteString(r.idents(f.columns))
+	}
+	if f.ondelete != "" {
+		b.WriteString(" " + r.keyword("ON DELETE "+f.ondelete))
+	}
+	if f.onupdate != "" {
+		b.WriteString(" " + r.keyword("ON UPDATE "+f.onupdate))
+	}
+	return b.String()
+}
+
+// idents returns the list of names quoted and written between parentheses.
+func (r *renderer) idents(names []string) string {
+	list := make([]string, len(names))
+	for i := range names {
+		list[i] = r.ident(names[i])
+	}
+	return "(" + strings.Join(list, ", ") + ")"
+}
+
+// renderDDL renders s that is part of a DDL statement. Bind parameters are
+// not accepted by the engines in those statements.
+func renderDDL(r *renderer, s SQLer) (string, error) {
+	sql, args, err := r.render(s)
+	if err == nil && len(args) > 0 {
+		err = fmt.Errorf("%w: arguments not supported in DDL statement", ErrArg)
+	}
+	return sql, err
+}
+
+type ColumnOption func(*columnDef) error
+
+// ColumnNull explicitly allows the column to be NULL.
+func ColumnNull() ColumnOption {
+	return func(c *columnDef) error {
+		c.null = nullable
+		return nil
+	}
+}
+
+func ColumnNotNull() ColumnOption {
+	return func(c *columnDef) error {
+		c.null = notNullable
+		return nil
+	}
+}
+
+// ColumnDefault sets the default value of the column. value can be a literal,
+// a function or any expression without arguments.
+func ColumnDefault(value SQLer) ColumnOption {
+	return func(c *columnDef) error {
+		if value == nil {
+			return fmt.Errorf("default: %w: no value given", ErrSyntax)
+		}
+		c.value = value
+		return nil
+	}
+}
+
+func ColumnPrimaryKey() ColumnOption {
+	return func(c *columnDef) error {
+		c.primary = true
+		return nil
+	}
+}
+
+// ColumnAutoIncrement lets the database generate the values of the column.
+// It is rendered as an identity column except for MySQL and SQLite. SQLite
+// requires the column to be the primary key.
+func ColumnAutoIncrement() ColumnOption {
+	return func(c *columnDef) error {
+		c.auto = true
+		return nil
+	}
+}
+
+func ColumnUnique() ColumnOption {
+	return func(c *columnDef) error {
+		c.unique = true
+		return nil
+	}
+}
+
+// ColumnCheck adds a CHECK constraint to the column. cdt is a relational
+// expression.
+func ColumnCheck(cdt SQLer) ColumnOption {
+	return func(c *columnDef) error {
+		if !acceptRelational(cdt) {
+			return fmt.Errorf("check: %w", ErrSyntax)
+		}
+		c.check = cdt
+		return nil
+	}
+}
+
+// ColumnReferences makes the column a foreign key referring to column of
+// table. column can be empty to refer to the primary key of table. With
+// MySQL, the reference is written as a table constraint.
+func ColumnReferences(table, column string, options ...ForeignKeyOption) ColumnOption {
+	return func(c *columnDef) error {
+		var columns []string
+		if column != "" {
+			columns = append(columns, column)
+		}
+		f, err := newForeignKey(table, columns, options)
+		if err == nil {
+			c.ref = &f
+		}
+		return err
+	}
+}
+
+const (
+	nullDefault uint8 = iota
+	nullable
+	notNullable
+)
+
+type columnDef struct {
+	name    string
+	kind    SQLer
+	null    uint8
+	value   SQLer
+	primary bool
+	auto    bool
+	unique  bool
+	check   SQLer
+	ref     *foreignKey
+}
+
+func newColumnDef(name string, kind SQLer, options []ColumnOption) (columnDef, error) {
+	c := columnDef{
+		name: name,
+		kind: kind,
+	}
+	if !isValidIdentifier(name) {
+		return c, fmt.Errorf("column: %w %q", ErrIdent, name)
+	}
+	if _, ok := kind.(columnType); !ok {
+		return c, fmt.Errorf("column: %w: invalid type", ErrSyntax)
+	}
+	for _, opt := range options {
+		if err := opt(&c); err != nil {
+			return c, err
+		}
+	}
+	return c, nil
+}
+
+func (c columnDef) SQL() (string, []interface{}, error) {
+	return SQLDialect(c, Default)
+}
+
+func (c columnDef) render(r *renderer) (string, []interface{}, error) {
+	var b strings.Builder
+	b.WriteString(r.ident(c.name))
+	b.WriteString(" ")
+	sql, err := renderDDL(r, c.kind)
+	if err != nil {
+		return "", nil, err
+	}
+	b.WriteString(sql)
+	switch c.null {
+	case nullable:
+		b.WriteString(" " + r.keyword("NULL"))
+	case notNullable:
+		b.WriteString(" " + r.keyword("NOT NULL"))
+	}
+	if c.value != nil {
+		sql, err := renderDDL(r, c.value)
+		if err != nil {
+			return "", nil, err
+		}
+		b.WriteString(" " + r.keyword("DEFAULT") + " ")
+		b.WriteString(sql)
+	}
+	if c.primary {
+		b.WriteString(" " + r.keyword("PRIMARY KEY"))
+	}
+	if c.auto {
+		switch {
+		case r.is(MySQL):
+			b.WriteString(" " + r.keyword("AUTO_INCREMENT"))
+		case r.is(SQLite):
+			if !c.primary {
+				return "", nil, fmt.Errorf("column: autoincrement requires primary key with SQLite")
+			}
+			b.WriteString(" " + r.keyword("AUTOINCREMENT"))
+		default:
+			b.WriteString(" " + r.keyword("GENERATED BY DEFAULT AS IDENTITY"))
+		}
+	}
+	if c.unique {
+		b.WriteString(" " + r.keyword("UNIQUE"))
+	}
+	if c.check != nil {
+		sql, err := renderDDL(r, c.check)
+		if err != nil {
+			return "", nil, err
+		}
+		b.WriteString(" " + r.keyword("CHECK") + " (")
+		b.WriteString(sql)
+		b.WriteString(")")
+	}
+	if c.ref != nil && !r.is(MySQL) {
+		b.WriteString(" ")
+		b.WriteString(c.ref.render(r))
+	}
+	return b.String(), nil, nil
+}
+
+// foreignKey returns the reference of the column as a table constraint. MySQL
+// accepts a reference written with the column but does not create the foreign
+// key, so it is given as a table constraint instead.
+func (c columnDef) foreignKey() (SQLer, bool) {
+	if c.ref == nil {
+		return nil, false
+	}
+	fk := constraint{
+		kind:    constraintForeign,
+		columns: []string{c.name},
+		ref:     *c.ref,
+	}
+	return fk, true
+}
+
+type CreateTableOption func(*CreateTable) error
+
+// CreateColumn adds a column to the table. kind is one of the values returned
+// by the Type functions.
+func CreateColumn(name string, kind SQLer, options ...ColumnOption) CreateTableOption {
+	return func(c *CreateTable) error {
+		for _, col := range c.columns {
+			if col, ok := col.(columnDef); ok && col.name == name {
+				return fmt.Errorf("column: %w %q: already defined", ErrColumn, name)
+			}
+		}
+		def, err := newColumnDef(name, kind, options)
+		if err == nil {
+			c.columns = append(c.columns, def)
+		}
+		return err
+	}
+}
+
+// CreateConstraint adds a table constraint created with PrimaryKey, Unique,
+// Check or ForeignKey.
+func CreateConstraint(cst SQLer) CreateTableOption {
+	return func(c *CreateTable) error {
+		x, ok := cst.(constraint)
+		if !ok {
+			return fmt.Errorf("constraint: %w: invalid constraint", ErrSyntax)
+		}
+		if x.err != nil {
+			return x.err
+		}
+		c.constraints = append(c.constraints, cst)
+		return nil
+	}
+}
+
+func CreateIfNotExists() CreateTableOption {
+	return func(c *CreateTable) error {
+		c.exists = true
+		return nil
+	}
+}
+
+type CreateTable struct {
+	table       SQLer
+	exists      bool
+	columns     []SQLer
+	constraints []SQLer
+	query       SQLer
+}
+
+func NewCreateTable(table string, options ...CreateTableOption) (CreateTable, error) {
+	var (
+		c   CreateTable
+		err error
+	)
+	c.table = NewIdent(table)
+	for _, opt := range options {
+		if err = opt(&c); err != nil {
+			break
+		}
+	}
+	if err == nil && len(c.columns) == 0 {
+		err = fmt.Errorf("%w: no columns given", ErrSyntax)
+	}
+	return c, err
+}
+
+func (c CreateTable) SQL() (string, []interface{}, error) {
+	return SQLDialect(c, Default)
+}
+
+func (c CreateTable) SQLDialect(d Dialect) (string, []interface{}, error) {
+	return SQLDialect(c, d)
+}
+
+func (c CreateTable) render(r *renderer) (string, []interface{}, error) {
+	var b strings.Builder
+	b.WriteString(r.keyword("CREATE TABLE") + " ")
+	if c.exists {
+		b.WriteString(r.keyword("IF NOT EXISTS") + " ")
+	}
+	sql, err := renderDDL(r, c.table)
+	if err != nil {
+		return "", nil, err
+	}
+	b.WriteString(sql)
+	if c.query != nil {
+		sql, err := renderDDL(r, c.query)
+		if err != nil {
+			return "", nil, err
+		}
+		b.WriteString(" " + r.keyword("AS"))
+		b.WriteString(r.sep())
+		b.WriteString(sql)
+		return b.String(), nil, nil
+	}
+	list := append(append([]SQLer{}, c.columns...), c.constraints...)
+	if r.is(MySQL) {
+		for _, col := range c.columns {
+			if def, ok := col.(columnDef); ok {
+				if fk, ok := def.foreignKey(); ok {
+					list = append(list, fk)
+				}
+			}
+		}
+	}
+	b.WriteString(" (")
+	r.depth++
+	for i, s := range list {
+		if i > 0 {
+			b.WriteString(",")
+			if !r.pretty {
+				b.WriteString(" ")
+			}
+		}
+		sql, err := renderDDL(r, s)
+		if err != nil {
+			return "", nil, err
+		}
+		if r.pretty {
+			b.WriteString(r.sep())
+		}
+		b.WriteString(sql)
+	}
+	r.depth--
+	if r.pretty {
+		b.WriteString(r.sep())
+	}
+	b.WriteString(")")
+	return b.String(), nil, nil
+}
+
+type AlterTableOption func(*AlterTable) error
+
+func AlterAddColumn(name string, kind SQLer, options ...ColumnOption) AlterTableOption {
+	return func(a *AlterTable) error {
+		def, err := newColumnDef(name, kind, options)
+		if err == nil {
+			a.actions = append(a.actions, alterAction{kind: alterAddColumn, def: def})
+		}
+		return err
+	}
+}
+
+func AlterDropColumn(name string) AlterTableOption {
+	return func(a *AlterTable) error {
+		if !isValidIdentifier(name) {
+			return fmt.Errorf("drop column: %w %q", ErrIdent, name)
+		}
+		a.actions = append(a.actions, alterAction{kind: alterDropColumn, name: name})
+		return nil
+	}
+}
+
+// AlterRenameColumn renames the column old to name. It can not be combined
+// with other changes.
+func AlterRenameColumn(old, name string) AlterTableOption {
+	return func(a *AlterTable) error {
+		for _, n := range []string{old, name} {
+			if !isValidIdentifier(n) {
+				return fmt.Errorf("rename column: %w %q", ErrIdent, n)
+			}
+		}
+		a.actions = append(a.actions, alterAction{kind: alterRenameColumn, name: old, rename: name})
+		return nil
+	}
+}
+
+// AlterAddConstraint adds a table constraint. It is not supported by SQLite.
+func AlterAddConstraint(cst SQLer) AlterTableOption {
+	return func(a *AlterTable) error {
+		x, ok := cst.(constraint)
+		if !ok {
+			return fmt.Errorf("constraint: %w: invalid constraint", ErrSyntax)
+		}
+		if x.err != nil {
+			return x.err
+		}
+		a.actions = append(a.actions, alterAction{kind: alterAddConstraint, def: cst})
+		return nil
+	}
+}
+
+const (
+	alterAddColumn uint8 = iota
+	alterDropColumn
+	alterRenameColumn
+	alterAddConstraint
+)
+
+type alterAction struct {
+	kind   uint8
+	name   string
+	rename string
+	def    SQLer
+}
+
+func (a alterAction) SQL() (string, []interface{}, error) {
+	return SQLDialect(a, Default)
+}
+
+func (a alterAction) render(r *renderer) (string, []interface{}, error) {
+	switch a.kind {
+	case alterAddColumn:
+		sql, err := renderDDL(r, a.def)
+		return r.keyword("ADD COLUMN") + " " + sql, nil, err
+	case alterDropColumn:
+		return r.keyword("DROP COLUMN") + " " + r.ident(a.name), nil, nil
+	case alterRenameColumn:
+		return fmt.Sprintf("%s %s %s %s", r.keyword("RENAME COLUMN"), r.ident(a.name), r.keyword("TO"), r.ident(a.rename)), nil, nil
+	case alterAddConstraint:
+		if r.is(SQLite) {
+			return "", nil, fmt.Errorf("alter table: add constraint not supported by SQLite")
+		}
+		sql, err := renderDDL(r, a.def)
+		return r.keyword("ADD") + " " + sql, nil, err
+	default:
+		return "", nil, fmt.Errorf("unsupported alter table action")
+	}
+}
+
+type AlterTable struct {
+	table   SQLer
+	actions []SQLer
+}
+
+func NewAlterTable(table string, options ...AlterTableOption) (AlterTable, error) {
+	var (
+		a   AlterTable
+		err error
+	)
+	a.table = NewIdent(table)
+	for _, opt := range options {
+		if err = opt(&a); err != nil {
+			break
+		}
+	}
+	if err != nil {
+		return a, err
+	}
+	if len(a.actions) == 0 {
+		return a, fmt.Errorf("%w: no changes given", ErrSyntax)
+	}
+	for _, x := range a.actions {
+		if x, ok := x.(alterAction); ok && x.kind == alterRenameColumn && len(a.actions) > 1 {
+			return a, fmt.Errorf("%w: rename column can not be combined with other changes", ErrSyntax)
+		}
+	}
+	return a, nil
+}
+
+func (a AlterTable) SQL() (string, []interface{}, error) {
+	return SQLDialect(a, Default)
+}
+
+func (a AlterTable) SQLDialect(d Dialect) (string, []interface{}, error) {
+	return SQLDialect(a, d)
+}
+
+func (a AlterTable) render(r *renderer) (string, []interface{}, error) {
+	if r.is(SQLite) && len(a.actions) > 1 {
+		return "", nil, fmt.Errorf("alter table: only one change supported by SQLite")
+	}
+	var b strings.Builder
+	b.WriteString(r.keyword("ALTER TABLE") + " ")
+	sql, err := renderDDL(r, a.table)
+	if err != nil {
+		return "", nil, err
+	}
+	b.WriteString(sql)
+	actions := a.actions
+	if r.is(MySQL) {
+		actions = nil
+		for _, x := range a.actions {
+			actions = append(actions, x)
+			x, ok := x.(alterAction)
+			if !ok || x.kind != alterAddColumn {
+				continue
+			}
+			if def, ok := x.def.(columnDef); ok {
+				if fk, ok := def.foreignKey(); ok {
+					actions = append(actions, alterAction{kind: alterAddConstraint, def: fk})
+				}
+			}
+		}
+	}
+	for i, x := range actions {
+		if i > 0 {
+			b.WriteString(",")
+		}
+		sql, err := renderDDL(r, x)
+		if err != nil {
+			return "", nil, err
+		}
+		b.WriteString(r.sep())
+		b.WriteString(sql)
+	}
+	return b.String(), nil, nil
+}
+
+type DropTableOption func(*DropTable) error
+
+func DropIfExists() DropTableOption {
+	return func(d *DropTable) error {
+		d.exists = true
+		return nil
+	}
+}
+
+// DropCascade drops the objects depending on the table. It is not supported
+// by SQLite.
+func DropCascade() DropTableOption {
+	return func(d *DropTable) error {
+		d.cascade = true
+		return nil
+	}
+}
+
+type DropTable struct {
+	table   SQLer
+	exists  bool
+	cascade bool
+}
+
+func NewDropTable(table string, options ...DropTableOption) (DropTable, error) {
+	var (
+		d   DropTable
+		err error
+	)
+	d.table = NewIdent(table)
+	for _, opt := range options {
+		if err = opt(&d); err != nil {
+			break
+		}
+	}
+	return d, err
+}
+
+func (d DropTable) SQL() (string, []interface{}, error) {
+	return SQLDialect(d, Default)
+}
+
+func (d DropTable) SQLDialect(dialect Dialect) (string, []interface{}, error) {
+	return SQLDialect(d, dialect)
+}
+
+func (d DropTable) render(r *renderer) (string, []interface{}, error) {
+	if d.cascade && r.is(SQLite) {
+		return "", nil, fmt.Errorf("drop table: cascade not supported by SQLite")
+	}
+	var b strings.Builder
+	b.WriteString(r.keyword("DROP TABLE") + " ")
+	if d.exists {
+		b.WriteString(r.keyword("IF EXISTS") + " ")
+	}
+	sql, err := renderDDL(r, d.table)
+	if err != nil {
+		return "", nil, err
+	}
+	b.WriteString(sql)
+	if d.cascade {
+		b.WriteString(" " + r.keyword("CASCADE"))
+	}
+	return b.String(), nil, nil
+}
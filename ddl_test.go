@@ -0,0 +1,231 @@
+package quel
+
+import (
+	"testing"
+)
+
+func TestCreateTable(t *testing.T) {
+	options := []CreateTableOption{
+		CreateIfNotExists(),
+		CreateColumn("id", TypeInt(), ColumnPrimaryKey(), ColumnAutoIncrement()),
+		CreateColumn("name", TypeVarchar(64), ColumnNotNull(), ColumnUnique()),
+		CreateColumn("age", TypeInt(), ColumnNull(), ColumnCheck(GreaterOrEqual(NewIdent("age"), NewLiteral(18)))),
+		CreateColumn("active", TypeBool(), ColumnNotNull(), ColumnDefault(NewLiteral(true))),
+		CreateColumn("created", TypeTimestamp(), ColumnDefault(Now())),
+		CreateColumn("team", TypeInt(), ColumnReferences("teams", "id", OnDelete(SetNull))),
+		CreateColumn("role", TypeInt()),
+		CreateConstraint(Constraint("users_role_fk", ForeignKey([]string{"role"}, "roles", []string{"id"}, OnDelete(Cascade), OnUpdate(Restrict)))),
+		CreateConstraint(Unique("name", "team")),
+		CreateConstraint(Check(LesserThan(NewIdent("age"), NewLiteral(150)))),
+	}
+	q, err := NewCreateTable("users", options...)
+	if err != nil {
+		t.Fatalf("error creating query! %s", err)
+	}
+	want := "CREATE TABLE IF NOT EXISTS users (id INTEGER PRIMARY KEY GENERATED BY DEFAULT AS IDENTITY, name VARCHAR(64) NOT NULL UNIQUE, age INTEGER NULL CHECK (age >= 18), active BOOLEAN NOT NULL DEFAULT true, created TIMESTAMP DEFAULT NOW(), team INTEGER REFERENCES teams(id) ON DELETE SET NULL, role INTEGER, CONSTRAINT users_role_fk FOREIGN KEY (role) REFERENCES roles(id) ON DELETE CASCADE ON UPDATE RESTRICT, UNIQUE (name, team), CHECK (age < 150))"
+	compareQueries(t, q, want, nil)
+
+	want = "CREATE TABLE IF NOT EXISTS `users` (`id` INT PRIMARY KEY AUTO_INCREMENT, `name` VARCHAR(64) NOT NULL UNIQUE, `age` INT NULL CHECK (`age` >= 18), `active` BOOLEAN NOT NULL DEFAULT TRUE, `created` TIMESTAMP DEFAULT NOW(), `team` INT, `role` INT, CONSTRAINT `users_role_fk` FOREIGN KEY (`role`) REFERENCES `roles`(`id`) ON DELETE CASCADE ON UPDATE RESTRICT, UNIQUE (`name`, `team`), CHECK (`age` < 150), FOREIGN KEY (`team`) REFERENCES `teams`(`id`) ON DELETE SET NULL)"
+	compareDialect(t, q, MySQL, want, nil)
+
+	q, _ = NewCreateTable("files", CreateColumn("id", TypeUUID(), ColumnPrimaryKey()), CreateColumn("data", TypeBlob()), CreateColumn("meta", TypeJSON()), CreateColumn("ttl", TypeInterval()))
+	compareDialect(t, q, Postgres, `CREATE TABLE "files" ("id" UUID PRIMARY KEY, "data" BYTEA, "meta" JSON, "ttl" INTERVAL)`, nil)
+	compareDialect(t, q, SQLite, `CREATE TABLE "files" ("id" TEXT PRIMARY KEY, "data" BLOB, "meta" TEXT, "ttl" TEXT)`, nil)
+
+	invalid := [][]CreateTableOption{
+		{},
+		{CreateColumn("id", NewIdent("int"))},
+		{CreateColumn("id", TypeInt(), ColumnCheck(NewIdent("id")))},
+		{CreateColumn("id", TypeInt()), CreateConstraint(PrimaryKey())},
+		{CreateColumn("id", TypeInt()), CreateConstraint(ForeignKey([]string{"id"}, "roles", []string{"id", "name"}))},
+		{CreateColumn("id", TypeInt(), ColumnReferences("roles", "id", OnDelete("DROP")))},
+		{CreateColumn("id", TypeInt()), CreateConstraint(NewIdent("id"))},
+	}
+	for _, options := range invalid {
+		if _, err := NewCreateTable("users", options...); err == nil {
+			t.Errorf("expected error when creating invalid table")
+		}
+	}
+	q, _ = NewCreateTable("users", CreateColumn("active", TypeBool(), ColumnDefault(Arg("active", true))))
+	if _, _, err := q.SQL(); err == nil {
+		t.Errorf("expected error when using arguments in DDL")
+	}
+	q, _ = NewCreateTable("users", CreateColumn("id", TypeInt(), ColumnAutoIncrement()))
+	if _, _, err := SQLDialect(q, SQLite); err == nil {
+		t.Errorf("expected error when using autoincrement without primary key with SQLite")
+	}
+}
+
+func TestAlterTable(t *testing.T) {
+	data := []struct {
+		Options []AlterTableOption
+		Dialect Dialect
+		Want    string
+	}{
+		{
+			Options: []AlterTableOption{
+				AlterAddColumn("email", TypeText(), ColumnNotNull(), ColumnDefault(NewLiteral(""))),
+				AlterDropColumn("mail"),
+				AlterAddConstraint(Constraint("users_email_key", Unique("email"))),
+			},
+			Dialect: Default,
+			Want:    "ALTER TABLE users ADD COLUMN email TEXT NOT NULL DEFAULT '', DROP COLUMN mail, ADD CONSTRAINT users_email_key UNIQUE (email)",
+		},
+		{
+			Options: []AlterTableOption{
+				AlterRenameColumn("mail", "email"),
+			},
+			Dialect: Postgres,
+			Want:    `ALTER TABLE "users" RENAME COLUMN "mail" TO "email"`,
+		},
+		{
+			Options: []AlterTableOption{
+				AlterAddColumn("team", TypeInt(), ColumnReferences("teams", "id", OnDelete(SetNull))),
+			},
+			Dialect: MySQL,
+			Want:    "ALTER TABLE `users` ADD COLUMN `team` INT, ADD FOREIGN KEY (`team`) REFERENCES `teams`(`id`) ON DELETE SET NULL",
+		},
+	}
+	for _, d := range data {
+		q, err := NewAlterTable("users", d.Options...)
+		if err != nil {
+			t.Errorf("error creating query! %s", err)
+			continue
+		}
+		compareDialect(t, q, d.Dialect, d.Want, nil)
+	}
+	if _, err := NewAlterTable("users", AlterRenameColumn("mail", "email"), AlterDropColumn("name")); err == nil {
+		t.Errorf("expected error when combining rename with other changes")
+	}
+	if _, err := NewAlterTable("users"); err == nil {
+		t.Errorf("expected error when no changes are given")
+	}
+	q, _ := NewAlterTable("users", AlterAddConstraint(Unique("email")))
+	if _, _, err := SQLDialect(q, SQLite); err == nil {
+		t.Errorf("expected error when adding constraint with SQLite")
+	}
+}
+
+func TestDropTable(t *testing.T) {
+	q, err := NewDropTable("users", DropIfExists(), DropCascade())
+	if err != nil {
+		t.Fatalf("error creating query! %s", err)
+	}
+	compareQueries(t, q, "DROP TABLE IF EXISTS users CASCADE", nil)
+	if _, _, err := SQLDialect(q, SQLite); err == nil {
+		t.Errorf("expected error when dropping with cascade with SQLite")
+	}
+}
+
+func TestCreateIndex(t *testing.T) {
+	data := []struct {
+		Options []CreateIndexOption
+		Dialect Dialect
+		Want    string
+	}{
+		{
+			Options: []CreateIndexOption{
+				IndexUnique(),
+				IndexColumns("email"),
+			},
+			Dialect: Default,
+			Want:    "CREATE UNIQUE INDEX users_idx ON users (email)",
+		},
+		{
+			Options: []CreateIndexOption{
+				IndexConcurrently(),
+				IndexIfNotExists(),
+				IndexExpr(Func("LOWER", NewIdent("email"))),
+				IndexColumns("team"),
+				IndexWhere(Equal(NewIdent("active"), NewLiteral(true))),
+			},
+			Dialect: Postgres,
+			Want:    `CREATE INDEX CONCURRENTLY IF NOT EXISTS "users_idx" ON "users" ((LOWER("email")), "team") WHERE "active" = TRUE`,
+		},
+		{
+			Options: []CreateIndexOption{
+				IndexExpr(Func("LOWER", NewIdent("email"))),
+			},
+			Dialect: MySQL,
+			Want:    "CREATE INDEX `users_idx` ON `users` ((LOWER(`email`)))",
+		},
+	}
+	for _, d := range data {
+		q, err := NewCreateIndex("users_idx", "users", d.Options...)
+		if err != nil {
+			t.Errorf("error creating query! %s", err)
+			continue
+		}
+		compareDialect(t, q, d.Dialect, d.Want, nil)
+	}
+	if _, err := NewCreateIndex("users_idx", "users"); err == nil {
+		t.Errorf("expected error when no columns are given")
+	}
+	if _, err := NewCreateIndex("users_idx", "users", IndexColumns("email"), IndexWhere(NewIdent("active"))); err == nil {
+		t.Errorf("expected error when giving an invalid predicate")
+	}
+	q, _ := NewCreateIndex("users_idx", "users", IndexColumns("email"), IndexWhere(Equal(NewIdent("active"), Arg("active", true))))
+	if _, _, err := q.SQL(); err == nil {
+		t.Errorf("expected error when using arguments in DDL")
+	}
+	if _, _, err := SQLDialect(q, MySQL); err == nil {
+		t.Errorf("expected error when creating partial index with MySQL")
+	}
+	q, _ = NewCreateIndex("users_idx", "users", IndexColumns("email"), IndexConcurrently())
+	if _, _, err := SQLDialect(q, SQLite); err == nil {
+		t.Errorf("expected error when creating index concurrently with SQLite")
+	}
+}
+
+func TestDropIndex(t *testing.T) {
+	q, err := NewDropIndex("users_idx", DropIndexTable("users"))
+	if err != nil {
+		t.Fatalf("error creating query! %s", err)
+	}
+	compareQueries(t, q, "DROP INDEX users_idx", nil)
+	compareDialect(t, q, MySQL, "DROP INDEX `users_idx` ON `users`", nil)
+
+	q, _ = NewDropIndex("users_idx", DropIndexIfExists(), DropIndexConcurrently())
+	compareDialect(t, q, Postgres, `DROP INDEX CONCURRENTLY IF EXISTS "users_idx"`, nil)
+	if _, _, err := SQLDialect(q, MySQL); err == nil {
+		t.Errorf("expected error when dropping index without table with MySQL")
+	}
+}
+
+func TestCreateView(t *testing.T) {
+	actives, _ := NewSelect("users", SelectColumns("id", "name"), SelectWhere(Equal(NewIdent("active"), NewLiteral(true))))
+
+	q, err := NewCreateView("actives", actives, ViewReplace(), ViewColumns("id", "name"))
+	if err != nil {
+		t.Fatalf("error creating query! %s", err)
+	}
+	compareQueries(t, q, "CREATE OR REPLACE VIEW actives(id, name) AS SELECT id, name FROM users WHERE active = true", nil)
+	if _, _, err := SQLDialect(q, SQLite); err == nil {
+		t.Errorf("expected error when replacing view with SQLite")
+	}
+
+	q, err = NewCreateMaterializedView("actives", actives, ViewIfNotExists())
+	if err != nil {
+		t.Fatalf("error creating query! %s", err)
+	}
+	compareDialect(t, q, Postgres, `CREATE MATERIALIZED VIEW IF NOT EXISTS "actives" AS SELECT "id", "name" FROM "users" WHERE "active" = TRUE`, nil)
+	if _, _, err := SQLDialect(q, MySQL); err == nil {
+		t.Errorf("expected error when creating materialized view with MySQL")
+	}
+
+	if _, err := NewCreateView("actives", actives, ViewColumns("id")); err == nil {
+		t.Errorf("expected error when columns mismatched")
+	}
+	if _, err := NewCreateMaterializedView("actives", actives, ViewReplace()); err == nil {
+		t.Errorf("expected error when replacing materialized view")
+	}
+
+	c, err := NewCreateTableAs("archives", actives, CreateIfNotExists())
+	if err != nil {
+		t.Fatalf("error creating query! %s", err)
+	}
+	compareQueries(t, c, "CREATE TABLE IF NOT EXISTS archives AS SELECT id, name FROM users WHERE active = true", nil)
+	if _, err := NewCreateTableAs("archives", actives, CreateColumn("id", TypeInt())); err == nil {
+		t.Errorf("expected error when giving columns with a query")
+	}
+}
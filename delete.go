@@ -20,6 +20,32 @@ func DeleteWhere(where SQLer) DeleteOption {
 	}
 }
 
+// DeleteUsing adds a table whose columns can be used in the WHERE clause. It
+// is rendered as USING except for MySQL where the tables are listed after
+// FROM. It is not supported by SQLite. A subquery needs an alias to be given
+// as source.
+func DeleteUsing(source SQLer) DeleteOption {
+	return func(d *Delete) error {
+		if !isSource(source) {
+			return fmt.Errorf("using: %w: source can not be joined", ErrSyntax)
+		}
+		d.sources = append(d.sources, query{table: source})
+		return nil
+	}
+}
+
+// DeleteWith adds a common table expression to the statement. See
+// SelectWith.
+func DeleteWith(name string, query SQLer, columns ...SQLer) DeleteOption {
+	return func(d *Delete) error {
+		c, err := newCte(name, query, columns)
+		if err == nil {
+			d.ctes = append(d.ctes, c)
+		}
+		return err
+	}
+}
+
 func DeleteAlias(alias string) DeleteOption {
 	return func(d *Delete) error {
 		if !isValidIdentifier(alias) {
@@ -38,7 +64,9 @@ func DeleteReturn(values ...SQLer) DeleteOption {
 }
 
 type Delete struct {
+	ctes      []SQLer
 	table     SQLer
+	sources   []query
 	where     SQLer
 	returning []SQLer
 }
@@ -57,21 +85,74 @@ func NewDelete(table string, options ...DeleteOption) (Delete, error) {
 	return d, err
 }
 
+// Apply returns a copy of d with options applied to it. d is left unmodified.
+func (d Delete) Apply(options ...DeleteOption) (Delete, error) {
+	base := d
+	base.ctes = append([]SQLer{}, d.ctes...)
+	base.sources = append([]query{}, d.sources...)
+	base.returning = append([]SQLer{}, d.returning...)
+	for _, opt := range options {
+		if err := opt(&base); err != nil {
+			return d, err
+		}
+	}
+	return base, nil
+}
+
 func (d Delete) SQL() (string, []interface{}, error) {
-	var (
-		b    strings.Builder
-		args []interface{}
-	)
-	b.WriteString("DELETE FROM ")
-	sql, as, err := d.table.SQL()
+	return SQLDialect(d, Default)
+}
+
+func (d Delete) SQLDialect(dialect Dialect) (string, []interface{}, error) {
+	return SQLDialect(d, dialect)
+}
+
+func (d Delete) render(r *renderer) (string, []interface{}, error) {
+	if len(d.sources) > 0 && r.is(SQLite) {
+		return "", nil, fmt.Errorf("delete: using not supported by SQLite")
+	}
+	var b strings.Builder
+	args, err := writeCtes(r, &b, d.ctes)
+	if err != nil {
+		return "", nil, err
+	}
+	b.WriteString(r.keyword("DELETE") + " ")
+	if len(d.sources) > 0 && r.is(MySQL) {
+		target := d.table
+		if a, ok := target.(alias); ok {
+			target = NewIdent(a.name)
+		}
+		sql, _, err := r.render(target)
+		if err != nil {
+			return "", nil, err
+		}
+		b.WriteString(sql)
+		b.WriteString(" ")
+	}
+	b.WriteString(r.keyword("FROM") + " ")
+	sql, as, err := r.render(d.table)
 	if err != nil {
 		return "", nil, err
 	}
 	args = append(args, as...)
 	b.WriteString(sql)
+	if len(d.sources) > 0 {
+		kw := "USING"
+		if r.is(MySQL) {
+			kw = ""
+			b.WriteString(", ")
+		} else {
+			b.WriteString(r.sep())
+		}
+		as, err := writeSources(r, &b, kw, d.sources)
+		if err != nil {
+			return "", nil, err
+		}
+		args = append(args, as...)
+	}
 	if d.where != nil {
-		b.WriteString(" WHERE ")
-		sql, as, err := d.where.SQL()
+		b.WriteString(r.clause("WHERE"))
+		sql, as, err := r.render(d.where)
 		if err != nil {
 			return "", nil, err
 		}
@@ -79,8 +160,8 @@ func (d Delete) SQL() (string, []interface{}, error) {
 		b.WriteString(sql)
 	}
 	if d.returning != nil {
-		b.WriteString(" RETURNING ")
-		as, err := writeSQL(&b, d.returning...)
+		b.WriteString(r.clause("RETURNING"))
+		as, err := writeSQL(r, &b, d.returning...)
 		if err != nil {
 			return "", nil, err
 		}
@@ -27,6 +27,14 @@ func TestDelete(t *testing.T) {
 			Table: "users",
 			Want:  "DELETE FROM users WHERE role = 'admin'",
 		},
+		{
+			Options: []DeleteOption{
+				DeleteWhere(Between(NewIdent("age"), Arg("min", 18), Arg("max", 65))),
+			},
+			Table: "users",
+			Want:  "DELETE FROM users WHERE age BETWEEN ? AND ?",
+			Args:  []interface{}{18, 65},
+		},
 		{
 			Options: []DeleteOption{
 				DeleteAlias("u"),
@@ -54,3 +62,69 @@ func TestDelete(t *testing.T) {
 		compareQueries(t, q, d.Want, d.Args)
 	}
 }
+
+func TestDeleteUsing(t *testing.T) {
+	options := []DeleteOption{
+		DeleteAlias("u"),
+		DeleteUsing(Alias("b", NewIdent("bans"))),
+		DeleteWhere(And(Equal(NewIdent("user", "b"), NewIdent("id", "u")), Equal(NewIdent("permanent", "b"), Arg("permanent", true)))),
+	}
+	q, err := NewDelete("users", options...)
+	if err != nil {
+		t.Fatalf("error creating query! %s", err)
+	}
+	compareQueries(t, q, "DELETE FROM users AS u USING bans AS b WHERE b.user = u.id AND b.permanent = ?", []interface{}{true})
+	compareDialect(t, q, MySQL, "DELETE `u` FROM `users` AS `u`, `bans` AS `b` WHERE `b`.`user` = `u`.`id` AND `b`.`permanent` = ?", []interface{}{true})
+	if _, _, err := SQLDialect(q, SQLite); err == nil {
+		t.Errorf("expected error when rendering delete using for SQLite")
+	}
+
+	bans, err := NewSelect("bans", SelectColumns("user"), SelectWhere(Equal(NewIdent("permanent"), Arg("permanent", true))))
+	if err != nil {
+		t.Fatalf("error creating query! %s", err)
+	}
+	q, err = NewDelete("users", DeleteUsing(Alias("b", bans)), DeleteWhere(Equal(NewIdent("user", "b"), NewIdent("id", "users"))))
+	if err != nil {
+		t.Fatalf("error creating query! %s", err)
+	}
+	compareQueries(t, q, "DELETE FROM users USING (SELECT user FROM bans WHERE permanent = ?) AS b WHERE b.user = users.id", []interface{}{true})
+	if _, err := NewDelete("users", DeleteUsing(bans)); err == nil {
+		t.Errorf("expected error when using a select without alias")
+	}
+}
+
+func TestStatementWith(t *testing.T) {
+	moved, _ := NewDelete("users", DeleteWhere(Equal(NewIdent("active"), Arg("active", false))), DeleteReturn(NewIdent("id"), NewIdent("name")))
+	olds, _ := NewSelect("olds", SelectColumns("id", "name"))
+
+	i, err := NewInsert("archives", InsertWith("olds", moved, NewIdent("id"), NewIdent("name")), InsertColumns("id", "name"), InsertSelect(olds))
+	if err != nil {
+		t.Fatalf("error creating query! %s", err)
+	}
+	want := `WITH "olds"("id", "name") AS (DELETE FROM "users" WHERE "active" = $1 RETURNING "id", "name") INSERT INTO "archives"("id", "name") SELECT "id", "name" FROM "olds"`
+	compareDialect(t, i, Postgres, want, []interface{}{false})
+
+	s, err := NewSelect("olds", SelectWith("olds", moved, NewIdent("id"), NewIdent("name")), SelectColumn(Count(NewIdent("id"))))
+	if err != nil {
+		t.Fatalf("error creating query! %s", err)
+	}
+	compareQueries(t, s, "WITH olds(id, name) AS (DELETE FROM users WHERE active = ? RETURNING id, name) SELECT COUNT(id) FROM olds", []interface{}{false})
+
+	admins, _ := NewSelect("roles", SelectColumns("user"), SelectWhere(Equal(NewIdent("name"), Arg("role", "admin"))))
+	ids, _ := NewSelect("admins", SelectColumns("id"))
+	u, err := NewUpdate("users", UpdateWith("admins", admins, NewIdent("id")), UpdateColumn("active", Arg("active", true)), UpdateWhere(In(NewIdent("id"), ids)))
+	if err != nil {
+		t.Fatalf("error creating query! %s", err)
+	}
+	compareQueries(t, u, "WITH admins(id) AS (SELECT user FROM roles WHERE name = ?) UPDATE users SET active = ? WHERE id IN (SELECT id FROM admins)", []interface{}{"admin", true})
+
+	d, err := NewDelete("users", DeleteWith("admins", admins, NewIdent("id")), DeleteWhere(NotIn(NewIdent("id"), ids)))
+	if err != nil {
+		t.Fatalf("error creating query! %s", err)
+	}
+	compareQueries(t, d, "WITH admins(id) AS (SELECT user FROM roles WHERE name = ?) DELETE FROM users WHERE id NOT IN (SELECT id FROM admins)", []interface{}{"admin"})
+
+	if _, err := NewDelete("users", DeleteWith("admins", NewIdent("roles"))); err == nil {
+		t.Errorf("expected error when using an invalid query in cte")
+	}
+}
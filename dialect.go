@@ -0,0 +1,457 @@
+package quel
+
+import (
+	"database/sql"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// Dialect controls the parts of the generated SQL that differ from one
+// database engine to another.
+type Dialect interface {
+	// Placeholder returns the bind parameter for the argument called name.
+	// pos is the position (starting at 1) of the argument in the list of
+	// arguments returned with the query. A numeric name can be used as the
+	// position instead; a query mixing such names with others is rejected.
+	Placeholder(name string, pos int) string
+	// Quote returns ident quoted as an identifier.
+	Quote(ident string) string
+	// Interval returns an interval literal of value unit(s).
+	Interval(value int, unit string) string
+	// Bool returns the literal representation of a boolean value.
+	Bool(value bool) string
+	// Paginate returns the clause used to limit the number of rows returned by
+	// a query. An empty string is returned if limit and offset are both 0.
+	Paginate(limit, offset int) string
+}
+
+// NamedDialect is implemented by the dialects that can refer more than once
+// to the same bind parameter in a query. Arguments sharing the same name are
+// then bound only once when the query is rendered by SQLNamed or Compile.
+type NamedDialect interface {
+	Dialect
+	// Named returns the bind parameter for the argument called name. pos is
+	// the position of the argument the first time name was seen in the query.
+	Named(name string, pos int) string
+}
+
+var (
+	// Default is the dialect used by the SQL method of each SQLer. It does not
+	// quote identifiers and uses ? as placeholder unless the name of the
+	// argument is a number.
+	Default Dialect = basic{}
+	// ANSI follows the SQL standard as closely as possible.
+	ANSI Dialect = ansi{}
+	// Postgres is the dialect of PostgreSQL.
+	Postgres Dialect = postgres{}
+	// MySQL is the dialect of MySQL and MariaDB.
+	MySQL Dialect = mysql{}
+	// SQLite is the dialect of SQLite.
+	SQLite Dialect = sqlite{}
+)
+
+// SQLDialect renders q with the given dialect. Default is used if d is nil.
+func SQLDialect(q SQLer, d Dialect) (string, []interface{}, error) {
+	if d == nil {
+		d = Default
+	}
+	r := renderer{
+		Dialect: d,
+	}
+	return r.render(q)
+}
+
+// SQLNamed renders q with the given dialect and returns its arguments with
+// their names. Arguments sharing the same name are bound only once. The
+// placeholders are given by the Named method of the dialect if it implements
+// NamedDialect. They are written as @name with Default. Other dialects, like
+// MySQL and ANSI, have no syntax for named parameters and give an error.
+func SQLNamed(q SQLer, d Dialect) (string, []sql.NamedArg, error) {
+	if d == nil {
+		d = Default
+	}
+	r := renderer{
+		Dialect: d,
+		named:   true,
+	}
+	if _, ok := d.(NamedDialect); !ok && !r.is(Default) {
+		return "", nil, fmt.Errorf("%w: named arguments not supported by dialect", ErrArg)
+	}
+	str, args, err := r.renderNamed(q)
+	if err != nil {
+		return "", nil, err
+	}
+	list := make([]sql.NamedArg, 0, len(args))
+	for _, a := range args {
+		n, ok := a.(sql.NamedArg)
+		if !ok {
+			return "", nil, fmt.Errorf("%w: unnamed argument %v", ErrArg, a)
+		}
+		list = append(list, n)
+	}
+	return str, list, nil
+}
+
+// Numbered returns a dialect that gives to each placeholder the position of
+// its argument in the query instead of the name it was created with. Each
+// argument is given the next position, even if its name is already used by
+// another argument. It lets queries built separately be composed without their
+// placeholders colliding.
+func Numbered(d Dialect) Dialect {
+	if d == nil {
+		d = Default
+	}
+	n := numbered{
+		Dialect: d,
+	}
+	if nd, ok := d.(NamedDialect); ok {
+		return numberedNamed{
+			numbered: n,
+			named:    nd,
+		}
+	}
+	return n
+}
+
+type numbered struct {
+	Dialect
+}
+
+func (n numbered) Placeholder(_ string, pos int) string {
+	return n.Dialect.Placeholder(strconv.Itoa(pos), pos)
+}
+
+type numberedNamed struct {
+	numbered
+	named NamedDialect
+}
+
+func (n numberedNamed) Named(name string, pos int) string {
+	return n.named.Named(name, pos)
+}
+
+type node interface {
+	render(*renderer) (string, []interface{}, error)
+}
+
+type slot struct {
+	pos   int
+	value interface{}
+}
+
+type renderer struct {
+	Dialect
+	count   int
+	named   bool
+	compile bool
+	slots   map[string]slot
+	params  []string
+	names   map[string]struct{}
+	probe   bool
+
+	numeric    bool
+	unnumbered bool
+
+	pretty bool
+	indent string
+	lower  bool
+	depth  int
+}
+
+func (r *renderer) render(s SQLer) (string, []interface{}, error) {
+	if n, ok := s.(node); ok {
+		return n.render(r)
+	}
+	sql, args, err := s.SQL()
+	if err == nil {
+		r.count += len(args)
+	}
+	return sql, args, err
+}
+
+// renderNamed renders q in two passes. The first one collects the names of the
+// arguments given by the user, so that the values of structs and maps can be
+// given names that do not collide with them in the second one.
+func (r *renderer) renderNamed(q SQLer) (string, []interface{}, error) {
+	probe := *r
+	probe.probe = true
+	probe.names = make(map[string]struct{})
+	if _, _, err := probe.render(q); err != nil {
+		return "", nil, err
+	}
+	r.names = probe.names
+	return r.render(q)
+}
+
+// bind returns the placeholder of an argument. Arguments sharing the same
+// name are bound once only when the query is rendered by SQLNamed or Compile.
+// Otherwise each argument is given its own position, so that queries built
+// separately can be composed even if they use the same names.
+func (r *renderer) bind(name string, value interface{}) (string, []interface{}, error) {
+	if r.probe {
+		r.names[name] = struct{}{}
+	}
+	if !r.named && !r.compile {
+		return r.positional(name, value)
+	}
+	if !isValidName(name) {
+		return "", nil, fmt.Errorf("%w: invalid name %q", ErrArg, name)
+	}
+	nd, ok := r.Dialect.(NamedDialect)
+	if !ok && !r.named {
+		return r.positional(name, value)
+	}
+	placeholder := func(pos int) string {
+		if nd != nil {
+			return nd.Named(name, pos)
+		}
+		return fmt.Sprintf("@%s", name)
+	}
+	if s, ok := r.slots[name]; ok {
+		if !r.compile && !reflect.DeepEqual(s.value, value) {
+			return "", nil, fmt.Errorf("%w: %q bound to different values", ErrArg, name)
+		}
+		return placeholder(s.pos), nil, nil
+	}
+	if r.slots == nil {
+		r.slots = make(map[string]slot)
+	}
+	r.count++
+	r.params = append(r.params, name)
+	r.slots[name] = slot{
+		pos:   r.count,
+		value: value,
+	}
+	if r.named {
+		value = sql.Named(name, value)
+	}
+	return placeholder(r.count), []interface{}{value}, nil
+}
+
+// positional returns the placeholder of an argument given by its position.
+// A numeric name is used by some dialects as the position of the argument.
+// Such names can not be mixed with the others, which get their position from
+// their order in the query, unless the query is rendered with Numbered.
+func (r *renderer) positional(name string, value interface{}) (string, []interface{}, error) {
+	switch r.Dialect.(type) {
+	case numbered, numberedNamed:
+	default:
+		if isNumeric(name) {
+			r.numeric = true
+		} else {
+			r.unnumbered = true
+		}
+		if r.numeric && r.unnumbered {
+			return "", nil, fmt.Errorf("%w: numeric and other names mixed", ErrArg)
+		}
+	}
+	r.count++
+	r.params = append(r.params, name)
+	return r.Placeholder(name, r.count), []interface{}{value}, nil
+}
+
+// is reports whether the query is rendered with d, once unwrapped from
+// Numbered. It is used by the statements whose syntax differs too much from
+// one engine to another to be described by the methods of Dialect.
+func (r *renderer) is(d Dialect) bool {
+	base := r.Dialect
+	for {
+		switch n := base.(type) {
+		case numberedNamed:
+			base = n.numbered.Dialect
+			continue
+		case numbered:
+			base = n.Dialect
+			continue
+		}
+		return base == d
+	}
+}
+
+func (r *renderer) ident(name string) string {
+	if name == "" || name[0] == star || isQuote(rune(name[0])) {
+		return name
+	}
+	parts := strings.Split(name, ".")
+	for i := range parts {
+		if parts[i] == "*" {
+			continue
+		}
+		parts[i] = r.Quote(parts[i])
+	}
+	return strings.Join(parts, ".")
+}
+
+type basic struct{}
+
+func (basic) Placeholder(name string, _ int) string {
+	if isNumeric(name) {
+		return fmt.Sprintf("$%s", name)
+	}
+	return "?"
+}
+
+func (basic) Quote(ident string) string {
+	return ident
+}
+
+func (basic) Interval(value int, unit string) string {
+	return fmt.Sprintf("INTERVAL %d %s", value, unit)
+}
+
+func (basic) Bool(value bool) string {
+	return strconv.FormatBool(value)
+}
+
+func (basic) Paginate(limit, offset int) string {
+	var parts []string
+	if limit > 0 {
+		parts = append(parts, fmt.Sprintf("LIMIT %d", limit))
+	}
+	if offset > 0 {
+		parts = append(parts, fmt.Sprintf("OFFSET %d", offset))
+	}
+	return strings.Join(parts, " ")
+}
+
+type ansi struct{}
+
+func (ansi) Placeholder(_ string, _ int) string {
+	return "?"
+}
+
+func (ansi) Quote(ident string) string {
+	return quoteWith(ident, dquote)
+}
+
+func (ansi) Interval(value int, unit string) string {
+	return fmt.Sprintf("INTERVAL '%d' %s", value, unit)
+}
+
+func (ansi) Bool(value bool) string {
+	return strings.ToUpper(strconv.FormatBool(value))
+}
+
+func (ansi) Paginate(limit, offset int) string {
+	var parts []string
+	if offset > 0 {
+		parts = append(parts, fmt.Sprintf("OFFSET %d ROWS", offset))
+	}
+	if limit > 0 {
+		parts = append(parts, fmt.Sprintf("FETCH FIRST %d ROWS ONLY", limit))
+	}
+	return strings.Join(parts, " ")
+}
+
+type postgres struct{}
+
+func (postgres) Placeholder(name string, pos int) string {
+	if isNumeric(name) {
+		return fmt.Sprintf("$%s", name)
+	}
+	return fmt.Sprintf("$%d", pos)
+}
+
+func (postgres) Named(_ string, pos int) string {
+	return fmt.Sprintf("$%d", pos)
+}
+
+func (postgres) Quote(ident string) string {
+	return quoteWith(ident, dquote)
+}
+
+func (postgres) Interval(value int, unit string) string {
+	return fmt.Sprintf("INTERVAL '%d %s'", value, strings.ToLower(unit))
+}
+
+func (postgres) Bool(value bool) string {
+	return strings.ToUpper(strconv.FormatBool(value))
+}
+
+func (postgres) Paginate(limit, offset int) string {
+	return basic{}.Paginate(limit, offset)
+}
+
+type mysql struct{}
+
+// maxRows is the value used by MySQL in its own documentation to retrieve all
+// the rows from an offset to the end of the result set.
+const maxRows = "18446744073709551615"
+
+func (mysql) Placeholder(_ string, _ int) string {
+	return "?"
+}
+
+func (mysql) Quote(ident string) string {
+	return quoteWith(ident, bquote)
+}
+
+func (mysql) Interval(value int, unit string) string {
+	return fmt.Sprintf("INTERVAL %d %s", value, unit)
+}
+
+func (mysql) Bool(value bool) string {
+	return strings.ToUpper(strconv.FormatBool(value))
+}
+
+func (mysql) Paginate(limit, offset int) string {
+	if limit <= 0 && offset <= 0 {
+		return ""
+	}
+	str := maxRows
+	if limit > 0 {
+		str = strconv.Itoa(limit)
+	}
+	if offset <= 0 {
+		return fmt.Sprintf("LIMIT %s", str)
+	}
+	return fmt.Sprintf("LIMIT %s OFFSET %d", str, offset)
+}
+
+type sqlite struct{}
+
+func (sqlite) Placeholder(name string, _ int) string {
+	if isNumeric(name) {
+		return fmt.Sprintf("?%s", name)
+	}
+	return "?"
+}
+
+func (sqlite) Named(name string, _ int) string {
+	return fmt.Sprintf(":%s", name)
+}
+
+func (sqlite) Quote(ident string) string {
+	return quoteWith(ident, dquote)
+}
+
+func (sqlite) Interval(value int, unit string) string {
+	return fmt.Sprintf("'%+d %s'", value, strings.ToLower(unit))
+}
+
+func (sqlite) Bool(value bool) string {
+	if value {
+		return "1"
+	}
+	return "0"
+}
+
+func (sqlite) Paginate(limit, offset int) string {
+	if limit <= 0 && offset <= 0 {
+		return ""
+	}
+	if offset <= 0 {
+		return fmt.Sprintf("LIMIT %d", limit)
+	}
+	if limit <= 0 {
+		limit = -1
+	}
+	return fmt.Sprintf("LIMIT %d OFFSET %d", limit, offset)
+}
+
+func quoteWith(ident string, quote rune) string {
+	q := string(quote)
+	return q + strings.ReplaceAll(ident, q, q+q) + q
+}
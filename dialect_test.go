@@ -0,0 +1,310 @@
+package quel
+
+import (
+	"database/sql"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestDialect(t *testing.T) {
+	t.Run("select", testDialectSelect)
+	t.Run("literal", testDialectLiteral)
+	t.Run("interval", testDialectInterval)
+	t.Run("paginate", testDialectPaginate)
+	t.Run("numbered", testDialectNumbered)
+	t.Run("named", testDialectNamed)
+}
+
+func testDialectSelect(t *testing.T) {
+	options := []SelectOption{
+		SelectColumn(NewIdent("id", "u")),
+		SelectColumn(NewIdent("name", "u")),
+		SelectAlias("u"),
+		SelectWhere(Equal(NewIdent("role", "u"), Arg("role", "admin"))),
+		SelectOrderBy(Asc("name")),
+	}
+	q, err := NewSelect("users", options...)
+	if err != nil {
+		t.Fatalf("error creating query! %s", err)
+	}
+	data := []struct {
+		Dialect Dialect
+		Want    string
+	}{
+		{
+			Dialect: Default,
+			Want:    "SELECT u.id, u.name FROM users AS u WHERE u.role = ? ORDER BY name ASC",
+		},
+		{
+			Dialect: ANSI,
+			Want:    `SELECT "u"."id", "u"."name" FROM "users" AS "u" WHERE "u"."role" = ? ORDER BY "name" ASC`,
+		},
+		{
+			Dialect: Postgres,
+			Want:    `SELECT "u"."id", "u"."name" FROM "users" AS "u" WHERE "u"."role" = $1 ORDER BY "name" ASC`,
+		},
+		{
+			Dialect: MySQL,
+			Want:    "SELECT `u`.`id`, `u`.`name` FROM `users` AS `u` WHERE `u`.`role` = ? ORDER BY `name` ASC",
+		},
+		{
+			Dialect: SQLite,
+			Want:    `SELECT "u"."id", "u"."name" FROM "users" AS "u" WHERE "u"."role" = ? ORDER BY "name" ASC`,
+		},
+	}
+	for _, d := range data {
+		compareDialect(t, q, d.Dialect, d.Want, []interface{}{"admin"})
+	}
+}
+
+func testDialectLiteral(t *testing.T) {
+	data := []struct {
+		Dialect Dialect
+		Want    string
+	}{
+		{Dialect: Default, Want: "true"},
+		{Dialect: ANSI, Want: "TRUE"},
+		{Dialect: Postgres, Want: "TRUE"},
+		{Dialect: MySQL, Want: "TRUE"},
+		{Dialect: SQLite, Want: "1"},
+	}
+	for _, d := range data {
+		compareDialect(t, NewLiteral(true), d.Dialect, d.Want, nil)
+	}
+}
+
+func testDialectInterval(t *testing.T) {
+	data := []struct {
+		Dialect Dialect
+		Want    string
+	}{
+		{Dialect: Default, Want: "INTERVAL 3 DAY"},
+		{Dialect: ANSI, Want: "INTERVAL '3' DAY"},
+		{Dialect: Postgres, Want: "INTERVAL '3 day'"},
+		{Dialect: MySQL, Want: "INTERVAL 3 DAY"},
+		{Dialect: SQLite, Want: "'+3 day'"},
+	}
+	for _, d := range data {
+		compareDialect(t, Days(3), d.Dialect, d.Want, nil)
+	}
+}
+
+func testDialectPaginate(t *testing.T) {
+	data := []struct {
+		Dialect Dialect
+		Limit   int
+		Offset  int
+		Want    string
+	}{
+		{Dialect: Default, Limit: 10, Offset: 20, Want: "SELECT * FROM users LIMIT 10 OFFSET 20"},
+		{Dialect: Default, Offset: 20, Want: "SELECT * FROM users OFFSET 20"},
+		{Dialect: ANSI, Limit: 10, Offset: 20, Want: `SELECT * FROM "users" OFFSET 20 ROWS FETCH FIRST 10 ROWS ONLY`},
+		{Dialect: Postgres, Limit: 10, Want: `SELECT * FROM "users" LIMIT 10`},
+		{Dialect: MySQL, Offset: 20, Want: "SELECT * FROM `users` LIMIT 18446744073709551615 OFFSET 20"},
+		{Dialect: SQLite, Offset: 20, Want: `SELECT * FROM "users" LIMIT -1 OFFSET 20`},
+	}
+	for _, d := range data {
+		q, err := NewSelect("users", SelectLimit(d.Limit), SelectOffset(d.Offset))
+		if err != nil {
+			t.Errorf("error creating query! %s", err)
+			continue
+		}
+		compareDialect(t, q, d.Dialect, d.Want, nil)
+	}
+}
+
+func testDialectNumbered(t *testing.T) {
+	active, err := NewSelect("users", SelectColumns("id"), SelectWhere(Equal(NewIdent("active"), Arg("1", true))))
+	if err != nil {
+		t.Fatalf("error creating query! %s", err)
+	}
+	roles, err := NewSelect("roles", SelectColumns("user"), SelectWhere(Equal(NewIdent("name"), Arg("1", "admin"))))
+	if err != nil {
+		t.Fatalf("error creating query! %s", err)
+	}
+	options := []SelectOption{
+		SelectWith("actives", active, NewIdent("id")),
+		SelectColumns("id"),
+		SelectWhere(In(NewIdent("id"), roles)),
+	}
+	q, err := NewSelect("actives", options...)
+	if err != nil {
+		t.Fatalf("error creating query! %s", err)
+	}
+	var (
+		want = "WITH actives(id) AS (SELECT id FROM users WHERE active = $1) SELECT id FROM actives WHERE id IN (SELECT user FROM roles WHERE name = $2)"
+		args = []interface{}{true, "admin"}
+	)
+	compareDialect(t, q, Numbered(Default), want, args)
+
+	want = `WITH "actives"("id") AS (SELECT "id" FROM "users" WHERE "active" = $1) SELECT "id" FROM "actives" WHERE "id" IN (SELECT "user" FROM "roles" WHERE "name" = $2)`
+	compareDialect(t, q, Numbered(Postgres), want, args)
+
+	u, err := Union(active, roles)
+	if err != nil {
+		t.Fatalf("error creating union! %s", err)
+	}
+	want = "SELECT id FROM users WHERE active = $1 UNION SELECT user FROM roles WHERE name = $2"
+	compareDialect(t, u, Numbered(Default), want, args)
+
+	want = "EXISTS (SELECT id FROM users WHERE active = $1) AND name = $2"
+	compareDialect(t, And(active.Exists(), Equal(NewIdent("name"), Arg("1", "admin"))), Numbered(Default), want, args)
+
+	i, err := NewInsert("users", InsertColumns("first", "last"), InsertValues(Arg("1", "roger"), Arg("2", "lamotte")), InsertValues(Arg("1", "pierre"), Arg("2", "dubois")))
+	if err != nil {
+		t.Fatalf("error creating query! %s", err)
+	}
+	want = "INSERT INTO users(first, last) VALUES ($1, $2), ($3, $4)"
+	compareDialect(t, i, Numbered(Default), want, []interface{}{"roger", "lamotte", "pierre", "dubois"})
+
+	members, err := NewSelect("members", SelectColumns("user"), SelectWhere(Equal(NewIdent("org"), Arg("id", 1))))
+	if err != nil {
+		t.Fatalf("error creating query! %s", err)
+	}
+	q, err = NewSelect("users", SelectWith("actives", active), SelectColumns("name"), SelectWhere(And(In(NewIdent("id"), members), Equal(NewIdent("team"), Arg("id", 2)))))
+	if err != nil {
+		t.Fatalf("error creating query! %s", err)
+	}
+	args = []interface{}{true, 1, 2}
+	want = `WITH "actives" AS (SELECT "id" FROM "users" WHERE "active" = $1) SELECT "name" FROM "users" WHERE "id" IN (SELECT "user" FROM "members" WHERE "org" = $2) AND "team" = $3`
+	compareDialect(t, q, Numbered(Postgres), want, args)
+	want = `WITH "actives" AS (SELECT "id" FROM "users" WHERE "active" = ?1) SELECT "name" FROM "users" WHERE "id" IN (SELECT "user" FROM "members" WHERE "org" = ?2) AND "team" = ?3`
+	compareDialect(t, q, Numbered(SQLite), want, args)
+}
+
+func testDialectNamed(t *testing.T) {
+	where := Or(Equal(NewIdent("first"), Arg("name", "roger")), Equal(NewIdent("last"), Arg("name", "roger")))
+	q, err := NewSelect("users", SelectColumns("id"), SelectWhere(where), SelectLimit(1))
+	if err != nil {
+		t.Fatalf("error creating query! %s", err)
+	}
+	data := []struct {
+		Dialect Dialect
+		Want    string
+		Args    []interface{}
+	}{
+		{
+			Dialect: Default,
+			Want:    "SELECT id FROM users WHERE first = ? OR last = ? LIMIT 1",
+			Args:    []interface{}{"roger", "roger"},
+		},
+		{
+			Dialect: MySQL,
+			Want:    "SELECT `id` FROM `users` WHERE `first` = ? OR `last` = ? LIMIT 1",
+			Args:    []interface{}{"roger", "roger"},
+		},
+		{
+			Dialect: Postgres,
+			Want:    `SELECT "id" FROM "users" WHERE "first" = $1 OR "last" = $2 LIMIT 1`,
+			Args:    []interface{}{"roger", "roger"},
+		},
+		{
+			Dialect: SQLite,
+			Want:    `SELECT "id" FROM "users" WHERE "first" = ? OR "last" = ? LIMIT 1`,
+			Args:    []interface{}{"roger", "roger"},
+		},
+	}
+	for _, d := range data {
+		compareDialect(t, q, d.Dialect, d.Want, d.Args)
+	}
+
+	named := []struct {
+		Dialect Dialect
+		Want    string
+	}{
+		{
+			Dialect: Default,
+			Want:    "SELECT id FROM users WHERE first = @name OR last = @name LIMIT 1",
+		},
+		{
+			Dialect: Postgres,
+			Want:    `SELECT "id" FROM "users" WHERE "first" = $1 OR "last" = $1 LIMIT 1`,
+		},
+		{
+			Dialect: Numbered(Postgres),
+			Want:    `SELECT "id" FROM "users" WHERE "first" = $1 OR "last" = $1 LIMIT 1`,
+		},
+		{
+			Dialect: SQLite,
+			Want:    `SELECT "id" FROM "users" WHERE "first" = :name OR "last" = :name LIMIT 1`,
+		},
+	}
+	for _, d := range named {
+		str, args, err := SQLNamed(q, d.Dialect)
+		if err != nil {
+			t.Errorf("%s: error building query! %s", d.Want, err)
+			continue
+		}
+		if str != d.Want {
+			t.Errorf("queries mismatched!")
+			t.Logf("\twant: %s", d.Want)
+			t.Logf("\tgot:  %s", str)
+		}
+		if want := []sql.NamedArg{sql.Named("name", "roger")}; !reflect.DeepEqual(args, want) {
+			t.Errorf("arguments mismatched! want %v, got %v", want, args)
+		}
+	}
+
+	for _, d := range []Dialect{MySQL, ANSI, Numbered(MySQL)} {
+		if _, _, err := SQLNamed(q, d); !errors.Is(err, ErrArg) {
+			t.Errorf("expected ErrArg for dialect without named parameters, got %v", err)
+		}
+	}
+
+	where = And(Equal(NewIdent("first"), Arg("name", "roger")), Equal(NewIdent("last"), Arg("name", "dubois")))
+	q, _ = NewSelect("users", SelectWhere(where))
+	if _, _, err := SQLNamed(q, Default); !errors.Is(err, ErrArg) {
+		t.Errorf("expected ErrArg for conflicting values, got %v", err)
+	}
+	if _, _, err := SQLNamed(q, Postgres); !errors.Is(err, ErrArg) {
+		t.Errorf("expected ErrArg for conflicting values, got %v", err)
+	}
+	compareDialect(t, q, Postgres, `SELECT * FROM "users" WHERE "first" = $1 AND "last" = $2`, []interface{}{"roger", "dubois"})
+
+	members, err := NewSelect("members", SelectColumns("user"), SelectWhere(Equal(NewIdent("org"), Arg("id", 1))))
+	if err != nil {
+		t.Fatalf("error creating query! %s", err)
+	}
+	q, err = NewSelect("users", SelectColumns("name"), SelectWhere(And(In(NewIdent("id"), members), Equal(NewIdent("team"), Arg("id", 2)))))
+	if err != nil {
+		t.Fatalf("error creating query! %s", err)
+	}
+	compareDialect(t, q, Postgres, `SELECT "name" FROM "users" WHERE "id" IN (SELECT "user" FROM "members" WHERE "org" = $1) AND "team" = $2`, []interface{}{1, 2})
+	compareDialect(t, q, SQLite, `SELECT "name" FROM "users" WHERE "id" IN (SELECT "user" FROM "members" WHERE "org" = ?) AND "team" = ?`, []interface{}{1, 2})
+	if _, _, err := SQLNamed(q, Postgres); !errors.Is(err, ErrArg) {
+		t.Errorf("expected ErrArg for conflicting values, got %v", err)
+	}
+	q, _ = NewSelect("users", SelectWhere(Equal(NewIdent("id"), Arg("1", 1))))
+	if _, _, err := SQLNamed(q, Default); !errors.Is(err, ErrArg) {
+		t.Errorf("expected ErrArg for invalid name, got %v", err)
+	}
+
+	mixed := And(Equal(NewIdent("a"), Arg("2", 1)), Equal(NewIdent("b"), Arg("x", 2)))
+	for _, d := range []Dialect{Default, Postgres, SQLite} {
+		if _, _, err := SQLDialect(mixed, d); !errors.Is(err, ErrArg) {
+			t.Errorf("expected ErrArg for numeric and other names mixed, got %v", err)
+		}
+	}
+	compareDialect(t, mixed, Numbered(Postgres), `"a" = $1 AND "b" = $2`, []interface{}{1, 2})
+}
+
+func compareDialect(t *testing.T, q SQLer, d Dialect, sql string, args []interface{}) {
+	t.Helper()
+	str, as, err := SQLDialect(q, d)
+	if err != nil {
+		t.Errorf("%s: error when building query: %s", sql, err)
+		return
+	}
+	if str != sql {
+		t.Errorf("queries mismatched!")
+		t.Logf("\twant: %s", sql)
+		t.Logf("\tgot:  %s", str)
+		return
+	}
+	if !reflect.DeepEqual(as, args) {
+		t.Errorf("arguments mismatched!")
+		t.Logf("\twant: %v", args)
+		t.Logf("\tgot:  %v", as)
+	}
+}
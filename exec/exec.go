@@ -0,0 +1,76 @@
+// Package exec runs the queries built with quel against a database/sql
+// connection.
+package exec
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/midbel/quel"
+)
+
+// Queryer is the set of methods shared by *sql.DB, *sql.Tx and *sql.Conn
+// needed to run a query.
+type Queryer interface {
+	ExecContext(context.Context, string, ...interface{}) (sql.Result, error)
+	QueryContext(context.Context, string, ...interface{}) (*sql.Rows, error)
+	QueryRowContext(context.Context, string, ...interface{}) *sql.Row
+}
+
+// Executor renders queries with its dialect and sends them to its Queryer.
+type Executor struct {
+	db      Queryer
+	dialect quel.Dialect
+}
+
+// New returns an Executor running queries on db. The queries are rendered with
+// the given dialect or with quel.Default if dialect is nil.
+func New(db Queryer, dialect quel.Dialect) Executor {
+	if dialect == nil {
+		dialect = quel.Default
+	}
+	return Executor{
+		db:      db,
+		dialect: dialect,
+	}
+}
+
+// Dialect returns the dialect used to render the queries.
+func (e Executor) Dialect() quel.Dialect {
+	return e.dialect
+}
+
+// With returns a copy of e running its queries on db, typically a transaction
+// started from the database used by e.
+func (e Executor) With(db Queryer) Executor {
+	return New(db, e.dialect)
+}
+
+// Exec runs a query that does not return rows.
+func (e Executor) Exec(ctx context.Context, q quel.SQLer) (sql.Result, error) {
+	str, args, err := quel.SQLDialect(q, e.dialect)
+	if err != nil {
+		return nil, err
+	}
+	return e.db.ExecContext(ctx, str, args...)
+}
+
+// Query runs a query returning rows.
+func (e Executor) Query(ctx context.Context, q quel.SQLer) (*sql.Rows, error) {
+	str, args, err := quel.SQLDialect(q, e.dialect)
+	if err != nil {
+		return nil, err
+	}
+	return e.db.QueryContext(ctx, str, args...)
+}
+
+// QueryRow runs a query expected to return at most one row. The error
+// returned is set only if the query can not be rendered, errors from the
+// database are deferred until the Scan method of the row is called.
+func (e Executor) QueryRow(ctx context.Context, q quel.SQLer) (*sql.Row, error) {
+	str, args, err := quel.SQLDialect(q, e.dialect)
+	if err != nil {
+		return nil, err
+	}
+	return e.db.QueryRowContext(ctx, str, args...), nil
+}
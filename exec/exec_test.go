@@ -0,0 +1,104 @@
+package exec
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/midbel/quel"
+)
+
+func TestExec(t *testing.T) {
+	fake := NewFake()
+	db := fake.DB()
+	defer db.Close()
+
+	var (
+		ctx = context.Background()
+		ex  = New(db, quel.Postgres)
+	)
+	q, err := quel.NewUpdate("users", quel.UpdateColumn("role", quel.Arg("role", "admin")), quel.UpdateWhere(quel.Equal(quel.NewIdent("id"), quel.Arg("id", 1))))
+	if err != nil {
+		t.Fatalf("error creating query! %s", err)
+	}
+	fake.AddResult(0, 1)
+	res, err := ex.Exec(ctx, q)
+	if err != nil {
+		t.Fatalf("error executing query! %s", err)
+	}
+	if n, _ := res.RowsAffected(); n != 1 {
+		t.Errorf("rows affected mismatched! want 1, got %d", n)
+	}
+	want := []Statement{
+		{
+			Query: `UPDATE "users" SET "role" = $1 WHERE "id" = $2`,
+			Args:  []interface{}{"admin", 1},
+		},
+	}
+	if got := fake.Statements(); !reflect.DeepEqual(got, want) {
+		t.Errorf("statements mismatched!")
+		t.Logf("\twant: %v", want)
+		t.Logf("\tgot:  %v", got)
+	}
+}
+
+func TestQuery(t *testing.T) {
+	fake := NewFake()
+	db := fake.DB()
+	defer db.Close()
+
+	var (
+		ctx = context.Background()
+		ex  = New(db, nil)
+	)
+	q, err := quel.NewSelect("users", quel.SelectColumns("id", "name"), quel.SelectWhere(quel.Equal(quel.NewIdent("role"), quel.Arg("role", "admin"))))
+	if err != nil {
+		t.Fatalf("error creating query! %s", err)
+	}
+	fake.AddRows([]string{"id", "name"}, []driver.Value{int64(1), "roger"}, []driver.Value{int64(2), "pierre"})
+	rows, err := ex.Query(ctx, q)
+	if err != nil {
+		t.Fatalf("error executing query! %s", err)
+	}
+	var names []string
+	for rows.Next() {
+		var (
+			id   int
+			name string
+		)
+		if err := rows.Scan(&id, &name); err != nil {
+			t.Fatalf("error scanning row! %s", err)
+		}
+		names = append(names, name)
+	}
+	rows.Close()
+	if want := []string{"roger", "pierre"}; !reflect.DeepEqual(names, want) {
+		t.Errorf("rows mismatched! want %v, got %v", want, names)
+	}
+
+	fake.AddRows([]string{"name"}, []driver.Value{"roger"})
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		t.Fatalf("error starting transaction! %s", err)
+	}
+	row, err := ex.With(tx).QueryRow(ctx, q)
+	if err != nil {
+		t.Fatalf("error executing query! %s", err)
+	}
+	var name string
+	if err := row.Scan(&name); err != nil || name != "roger" {
+		t.Errorf("unexpected row! %q (%v)", name, err)
+	}
+	tx.Commit()
+
+	errFake := errors.New("fake")
+	fake.AddError(errFake)
+	if _, err := ex.Query(ctx, q); !errors.Is(err, errFake) {
+		t.Errorf("expected fake error, got %v", err)
+	}
+	if got := len(fake.Statements()); got != 3 {
+		t.Errorf("statements count mismatched! want 3, got %d", got)
+	}
+}
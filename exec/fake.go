@@ -0,0 +1,239 @@
+package exec
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"sync"
+)
+
+// Statement is a query received by a Fake database.
+type Statement struct {
+	Query string
+	Args  []interface{}
+}
+
+type response struct {
+	columns  []string
+	rows     [][]driver.Value
+	affected int64
+	lastID   int64
+	err      error
+}
+
+// Fake is a database/sql driver that records the statements it receives and
+// answers them with the responses queued with AddRows, AddResult and AddError.
+// Without response queued, queries return no rows and statements report no
+// row affected.
+type Fake struct {
+	mu         sync.Mutex
+	statements []Statement
+	responses  []response
+}
+
+// NewFake returns a new Fake database.
+func NewFake() *Fake {
+	return &Fake{}
+}
+
+// DB returns a *sql.DB connected to f.
+func (f *Fake) DB() *sql.DB {
+	return sql.OpenDB(f)
+}
+
+// AddRows queues the rows returned by the next statement.
+func (f *Fake) AddRows(columns []string, rows ...[]driver.Value) {
+	f.push(response{
+		columns: append([]string{}, columns...),
+		rows:    rows,
+	})
+}
+
+// AddResult queues the result returned by the next statement.
+func (f *Fake) AddResult(lastID, affected int64) {
+	f.push(response{
+		lastID:   lastID,
+		affected: affected,
+	})
+}
+
+// AddError queues the error returned by the next statement.
+func (f *Fake) AddError(err error) {
+	f.push(response{
+		err: err,
+	})
+}
+
+// Statements returns the statements received by f in the order they were
+// received.
+func (f *Fake) Statements() []Statement {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]Statement{}, f.statements...)
+}
+
+// Connect implements driver.Connector.
+func (f *Fake) Connect(context.Context) (driver.Conn, error) {
+	return fakeConn{fake: f}, nil
+}
+
+// Driver implements driver.Connector.
+func (f *Fake) Driver() driver.Driver {
+	return fakeDriver{fake: f}
+}
+
+func (f *Fake) push(r response) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.responses = append(f.responses, r)
+}
+
+func (f *Fake) receive(query string, args []driver.NamedValue) response {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	s := Statement{
+		Query: query,
+	}
+	for _, a := range args {
+		if a.Name != "" {
+			s.Args = append(s.Args, sql.Named(a.Name, a.Value))
+			continue
+		}
+		s.Args = append(s.Args, a.Value)
+	}
+	f.statements = append(f.statements, s)
+
+	var r response
+	if len(f.responses) > 0 {
+		r, f.responses = f.responses[0], f.responses[1:]
+	}
+	return r
+}
+
+type fakeDriver struct {
+	fake *Fake
+}
+
+func (d fakeDriver) Open(string) (driver.Conn, error) {
+	return fakeConn{fake: d.fake}, nil
+}
+
+type fakeConn struct {
+	fake *Fake
+}
+
+func (c fakeConn) Prepare(query string) (driver.Stmt, error) {
+	return fakeStmt{fake: c.fake, query: query}, nil
+}
+
+func (c fakeConn) Close() error {
+	return nil
+}
+
+func (c fakeConn) Begin() (driver.Tx, error) {
+	return fakeTx{}, nil
+}
+
+func (c fakeConn) CheckNamedValue(*driver.NamedValue) error {
+	return nil
+}
+
+func (c fakeConn) ExecContext(_ context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	r := c.fake.receive(query, args)
+	if r.err != nil {
+		return nil, r.err
+	}
+	return fakeResult(r), nil
+}
+
+func (c fakeConn) QueryContext(_ context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	r := c.fake.receive(query, args)
+	if r.err != nil {
+		return nil, r.err
+	}
+	return &fakeRows{
+		columns: r.columns,
+		rows:    r.rows,
+	}, nil
+}
+
+type fakeStmt struct {
+	fake  *Fake
+	query string
+}
+
+func (s fakeStmt) Close() error {
+	return nil
+}
+
+func (s fakeStmt) NumInput() int {
+	return -1
+}
+
+func (s fakeStmt) Exec(args []driver.Value) (driver.Result, error) {
+	return fakeConn{fake: s.fake}.ExecContext(context.Background(), s.query, namedValues(args))
+}
+
+func (s fakeStmt) Query(args []driver.Value) (driver.Rows, error) {
+	return fakeConn{fake: s.fake}.QueryContext(context.Background(), s.query, namedValues(args))
+}
+
+type fakeTx struct{}
+
+func (fakeTx) Commit() error {
+	return nil
+}
+
+func (fakeTx) Rollback() error {
+	return nil
+}
+
+type fakeResult response
+
+func (r fakeResult) LastInsertId() (int64, error) {
+	return r.lastID, nil
+}
+
+func (r fakeResult) RowsAffected() (int64, error) {
+	return r.affected, nil
+}
+
+type fakeRows struct {
+	columns []string
+	rows    [][]driver.Value
+}
+
+func (r *fakeRows) Columns() []string {
+	return r.columns
+}
+
+func (r *fakeRows) Close() error {
+	return nil
+}
+
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if len(r.rows) == 0 {
+		return io.EOF
+	}
+	row := r.rows[0]
+	if len(row) != len(dest) {
+		return errors.New("fake: row and columns count mismatch")
+	}
+	copy(dest, row)
+	r.rows = r.rows[1:]
+	return nil
+}
+
+func namedValues(args []driver.Value) []driver.NamedValue {
+	list := make([]driver.NamedValue, len(args))
+	for i := range args {
+		list[i] = driver.NamedValue{
+			Ordinal: i + 1,
+			Value:   args[i],
+		}
+	}
+	return list
+}
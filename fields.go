@@ -0,0 +1,276 @@
+package quel
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+const tagName = "db"
+
+type field struct {
+	name      string
+	index     []int
+	omitempty bool
+	pk        bool
+}
+
+type mapper struct {
+	zero bool
+	keys bool
+}
+
+// StructOption configures how the fields of a struct are turned into columns
+// and values by InsertStruct and UpdateStruct.
+type StructOption func(*mapper)
+
+// SkipZero skips the fields whose value is the zero value of their type.
+func SkipZero() StructOption {
+	return func(m *mapper) {
+		m.zero = true
+	}
+}
+
+// SkipKeys skips the fields tagged as primary key with the pk option.
+func SkipKeys() StructOption {
+	return func(m *mapper) {
+		m.keys = true
+	}
+}
+
+// structFields returns the fields of t that can be mapped to a column. The
+// name of the column is given by the db tag of the field or by its name in
+// lower case if the tag is not set. Fields tagged with "-" are skipped and the
+// fields of embedded structs are promoted. The options following the name in
+// the tag are:
+//
+//	omitempty: the field is skipped if its value is the zero value of its type
+//	pk: the field is part of the primary key
+func structFields(t reflect.Type) []field {
+	var list []field
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag, ok := f.Tag.Lookup(tagName)
+		if tag == "-" {
+			continue
+		}
+		name, opts, _ := strings.Cut(tag, ",")
+		if f.Anonymous && !ok {
+			ft := f.Type
+			if ft.Kind() == reflect.Ptr {
+				// a nil pointer to an unexported struct can not be allocated
+				if !f.IsExported() {
+					continue
+				}
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct {
+				for _, e := range structFields(ft) {
+					e.index = append([]int{i}, e.index...)
+					list = append(list, e)
+				}
+				continue
+			}
+		}
+		if !f.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = strings.ToLower(f.Name)
+		}
+		fd := field{
+			name:  name,
+			index: []int{i},
+		}
+		for _, o := range strings.Split(opts, ",") {
+			switch o {
+			case "omitempty":
+				fd.omitempty = true
+			case "pk":
+				fd.pk = true
+			default:
+			}
+		}
+		list = append(list, fd)
+	}
+	return list
+}
+
+// structColumns returns the fields of v mapped to a column. Fields marked
+// with omitempty are skipped when their value is the zero value of their type.
+func structColumns(v interface{}) ([]field, reflect.Value, error) {
+	val, err := structValue(v)
+	if err != nil {
+		return nil, val, err
+	}
+	var list []field
+	for _, f := range structFields(val.Type()) {
+		fv, ok := fieldByIndex(val, f.index)
+		if f.omitempty && (!ok || fv.IsZero()) {
+			continue
+		}
+		if !isValidIdentifier(f.name) {
+			return nil, val, fmt.Errorf("column: %w %q", ErrIdent, f.name)
+		}
+		list = append(list, f)
+	}
+	return list, val, nil
+}
+
+// structRows returns the columns and the values of v, a struct, a pointer to a
+// struct or a slice of them. With multiple rows, a field is skipped because of
+// its zero value only if it is zero in every row.
+func structRows(v interface{}, options []StructOption) ([]string, [][]interface{}, error) {
+	var m mapper
+	for _, opt := range options {
+		opt(&m)
+	}
+	var rows []reflect.Value
+	switch val := reflect.ValueOf(v); val.Kind() {
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < val.Len(); i++ {
+			r, err := structValue(val.Index(i).Interface())
+			if err != nil {
+				return nil, nil, err
+			}
+			if len(rows) > 0 && r.Type() != rows[0].Type() {
+				return nil, nil, fmt.Errorf("%w: rows of different types", ErrArg)
+			}
+			rows = append(rows, r)
+		}
+	default:
+		r, err := structValue(v)
+		if err != nil {
+			return nil, nil, err
+		}
+		rows = append(rows, r)
+	}
+	if len(rows) == 0 {
+		return nil, nil, fmt.Errorf("%w: no rows given", ErrArg)
+	}
+	var (
+		columns []string
+		values  = make([][]interface{}, len(rows))
+	)
+	for _, f := range structFields(rows[0].Type()) {
+		if m.keys && f.pk {
+			continue
+		}
+		var (
+			vs   = make([]interface{}, len(rows))
+			zero = true
+		)
+		for i := range rows {
+			fv, ok := fieldByIndex(rows[i], f.index)
+			if !ok {
+				continue
+			}
+			zero = zero && fv.IsZero()
+			vs[i] = fv.Interface()
+		}
+		if zero && (m.zero || f.omitempty) {
+			continue
+		}
+		if !isValidIdentifier(f.name) {
+			return nil, nil, fmt.Errorf("column: %w %q", ErrIdent, f.name)
+		}
+		columns = append(columns, f.name)
+		for i := range vs {
+			values[i] = append(values[i], vs[i])
+		}
+	}
+	return columns, values, nil
+}
+
+// mapRow returns the keys of m sorted in lexical order and their values.
+func mapRow(m map[string]interface{}) ([]string, []interface{}, error) {
+	columns := make([]string, 0, len(m))
+	for k := range m {
+		if !isValidIdentifier(k) {
+			return nil, nil, fmt.Errorf("column: %w %q", ErrIdent, k)
+		}
+		columns = append(columns, k)
+	}
+	sort.Strings(columns)
+	values := make([]interface{}, len(columns))
+	for i, c := range columns {
+		values[i] = m[c]
+	}
+	return columns, values, nil
+}
+
+// argValue returns value if it is already a SQLer or an argument called name
+// otherwise.
+func argValue(name string, value interface{}) SQLer {
+	if s, ok := value.(SQLer); ok {
+		return s
+	}
+	return valueArg{
+		arg: arg{
+			name:  name,
+			value: value,
+		},
+	}
+}
+
+// valueArg is an argument holding a value of a struct or a map. Unlike the
+// arguments created with Arg, its name is only a hint: when it is already used
+// by another argument of the query, it is suffixed by _ and a number.
+type valueArg struct {
+	arg
+}
+
+func (a valueArg) SQL() (string, []interface{}, error) {
+	return SQLDialect(a, Default)
+}
+
+func (a valueArg) render(r *renderer) (string, []interface{}, error) {
+	if r.probe {
+		return "", nil, nil
+	}
+	if !r.named && !r.compile {
+		return a.arg.render(r)
+	}
+	name := a.name
+	for i := 1; ; i++ {
+		if _, ok := r.names[name]; !ok {
+			break
+		}
+		name = fmt.Sprintf("%s_%d", a.name, i)
+	}
+	a.name = name
+	return a.arg.render(r)
+}
+
+func structValue(v interface{}) (reflect.Value, error) {
+	val := reflect.ValueOf(v)
+	if !val.IsValid() {
+		return val, fmt.Errorf("%w: nil value", ErrArg)
+	}
+	for val.Kind() == reflect.Ptr {
+		if val.IsNil() {
+			return val, fmt.Errorf("%w: nil pointer", ErrArg)
+		}
+		val = val.Elem()
+	}
+	if val.Kind() != reflect.Struct {
+		return val, fmt.Errorf("%w: %s is not a struct", ErrArg, val.Type())
+	}
+	return val, nil
+}
+
+// fieldByIndex is like reflect.Value.FieldByIndex but reports whether one of
+// the embedded pointers traversed is nil instead of panicking.
+func fieldByIndex(v reflect.Value, index []int) (reflect.Value, bool) {
+	for i, x := range index {
+		if i > 0 && v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				return v, false
+			}
+			v = v.Elem()
+		}
+		v = v.Field(x)
+	}
+	return v, true
+}
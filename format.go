@@ -0,0 +1,90 @@
+package quel
+
+import (
+	"strings"
+)
+
+// FormatOptions controls the layout of the SQL produced by Format.
+type FormatOptions struct {
+	// Dialect used to render the query. Default is used if not set.
+	Dialect Dialect
+	// Indent is the string written once per level of nesting of subqueries.
+	// Two spaces are used if not set.
+	Indent string
+	// Lowercase writes the keywords in lower case instead of upper case.
+	Lowercase bool
+}
+
+// Format renders q like SQLDialect but writes each clause on its own line and
+// indents the subqueries. The arguments returned are the same as the ones
+// returned by SQLDialect.
+func Format(q SQLer, opts FormatOptions) (string, []interface{}, error) {
+	if opts.Dialect == nil {
+		opts.Dialect = Default
+	}
+	if opts.Indent == "" {
+		opts.Indent = "  "
+	}
+	r := renderer{
+		Dialect: opts.Dialect,
+		pretty:  true,
+		indent:  opts.Indent,
+		lower:   opts.Lowercase,
+	}
+	return r.render(q)
+}
+
+// keyword returns str in the case requested by the options given to Format.
+func (r *renderer) keyword(str string) string {
+	if r.lower {
+		return strings.ToLower(str)
+	}
+	return str
+}
+
+// sep returns the separator written between two clauses of a statement.
+func (r *renderer) sep() string {
+	if !r.pretty {
+		return " "
+	}
+	return "\n" + strings.Repeat(r.indent, r.depth)
+}
+
+// clause returns the separator and the keyword starting a clause.
+func (r *renderer) clause(kw string) string {
+	return r.sep() + r.keyword(kw) + " "
+}
+
+// align returns kw followed by the spaces needed to fill width when the
+// statement is formatted.
+func (r *renderer) align(kw string, width int) string {
+	kw = r.keyword(kw)
+	if !r.pretty || len(kw) >= width {
+		return kw
+	}
+	return kw + strings.Repeat(" ", width-len(kw))
+}
+
+// subquery renders s one level deeper than the current statement. The result
+// is expected to be written between parentheses.
+func (r *renderer) subquery(s SQLer) (string, []interface{}, error) {
+	if !r.pretty || !isStatement(s) {
+		return r.render(s)
+	}
+	r.depth++
+	sql, args, err := r.render(s)
+	r.depth--
+	if err != nil {
+		return "", nil, err
+	}
+	return r.sep() + r.indent + sql + r.sep(), args, nil
+}
+
+func isStatement(s SQLer) bool {
+	switch s.(type) {
+	case Select, union, Insert, Update, Delete, Merge:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,115 @@
+package quel
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestFormat(t *testing.T) {
+	roles, err := NewSelect("roles", SelectColumns("user"), SelectWhere(Equal(NewIdent("name"), Arg("role", "admin"))))
+	if err != nil {
+		t.Fatalf("error creating query! %s", err)
+	}
+	active, err := NewSelect("users", SelectColumns("id"), SelectWhere(Equal(NewIdent("active"), Arg("active", true))))
+	if err != nil {
+		t.Fatalf("error creating query! %s", err)
+	}
+	options := []SelectOption{
+		SelectWith("actives", active, NewIdent("id")),
+		SelectColumn(NewIdent("id", "u")),
+		SelectColumn(NewIdent("name", "p")),
+		SelectAlias("u"),
+	}
+	q, err := NewSelect("actives", options...)
+	if err != nil {
+		t.Fatalf("error creating query! %s", err)
+	}
+	q, err = q.LeftOuterJoin(Alias("p", NewIdent("positions")), Equal(NewIdent("id", "u"), NewIdent("user", "p")))
+	if err != nil {
+		t.Fatalf("error joining query! %s", err)
+	}
+	q.where = In(NewIdent("id", "u"), roles)
+	q.orderby = []SQLer{Asc("id")}
+	q.limit = 10
+
+	const want = `WITH actives(id) AS (
+  SELECT id
+  FROM users
+  WHERE active = ?
+)
+SELECT u.id, p.name
+FROM            actives AS u
+LEFT OUTER JOIN positions AS p ON u.id = p.user
+WHERE u.id IN (
+  SELECT user
+  FROM roles
+  WHERE name = ?
+)
+ORDER BY id ASC
+LIMIT 10`
+
+	str, args, err := Format(q, FormatOptions{})
+	if err != nil {
+		t.Fatalf("error formatting query! %s", err)
+	}
+	if str != want {
+		t.Errorf("queries mismatched!")
+		t.Logf("\twant: %s", want)
+		t.Logf("\tgot:  %s", str)
+	}
+	_, as, _ := q.SQL()
+	if !reflect.DeepEqual(args, as) {
+		t.Errorf("arguments mismatched! want %v, got %v", as, args)
+	}
+
+	const lower = "update \"users\"\nset \"active\" = false\nwhere \"id\" in (\n\tselect \"user\"\n\tfrom \"roles\"\n\twhere \"name\" = $1\n)"
+	u, err := NewUpdate("users", UpdateColumn("active", NewLiteral(false)), UpdateWhere(In(NewIdent("id"), roles)))
+	if err != nil {
+		t.Fatalf("error creating query! %s", err)
+	}
+	str, args, err = Format(u, FormatOptions{Dialect: Postgres, Indent: "\t", Lowercase: true})
+	if err != nil {
+		t.Fatalf("error formatting query! %s", err)
+	}
+	if str != lower {
+		t.Errorf("queries mismatched!")
+		t.Logf("\twant: %s", lower)
+		t.Logf("\tgot:  %s", str)
+	}
+	if want := []interface{}{"admin"}; !reflect.DeepEqual(args, want) {
+		t.Errorf("arguments mismatched! want %v, got %v", want, args)
+	}
+
+	const joined = "UPDATE     `users`\nINNER JOIN `bans` ON `bans`.`user` = `users`.`id`\nSET `active` = FALSE"
+	u, err = NewUpdate("users", UpdateColumn("active", NewLiteral(false)), UpdateJoin(NewIdent("bans"), Equal(NewIdent("user", "bans"), NewIdent("id", "users"))))
+	if err != nil {
+		t.Fatalf("error creating query! %s", err)
+	}
+	str, _, err = Format(u, FormatOptions{Dialect: MySQL})
+	if err != nil {
+		t.Fatalf("error formatting query! %s", err)
+	}
+	if str != joined {
+		t.Errorf("queries mismatched!")
+		t.Logf("\twant: %s", joined)
+		t.Logf("\tgot:  %s", str)
+	}
+
+	for _, sql := range []string{
+		"SELECT a FROM t WHERE a = 1 AND NOT (b = 2 OR c = 3)",
+		"SELECT a FROM t WHERE NOT (a = 1 AND b = 2)",
+	} {
+		q, err := Parse(sql, Default)
+		if err != nil {
+			t.Fatalf("error parsing query! %s", err)
+		}
+		str, _, err := Format(q, FormatOptions{})
+		if err != nil {
+			t.Fatalf("error formatting query! %s", err)
+		}
+		if q, err = Parse(str, Default); err != nil {
+			t.Fatalf("error parsing formatted query! %s", err)
+		}
+		compareQueries(t, q, sql, nil)
+	}
+}
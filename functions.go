@@ -1,6 +1,7 @@
 package quel
 
 import (
+	"fmt"
 	"strings"
 )
 
@@ -21,13 +22,17 @@ func (f function) Alias(name string) SQLer {
 }
 
 func (f function) SQL() (string, []interface{}, error) {
+	return SQLDialect(f, Default)
+}
+
+func (f function) render(r *renderer) (string, []interface{}, error) {
 	var (
 		b    strings.Builder
 		args []interface{}
 	)
 	b.WriteString(f.name)
 	b.WriteString("(")
-	as, err := writeSQL(&b, f.args...)
+	as, err := writeSQL(r, &b, f.args...)
 	if err != nil {
 		return "", nil, err
 	}
@@ -36,6 +41,75 @@ func (f function) SQL() (string, []interface{}, error) {
 	return b.String(), args, nil
 }
 
+type distinct struct {
+	expr SQLer
+}
+
+// Distinct restricts an aggregate function to the distinct values of expr, eg
+// Count(Distinct(NewIdent("role"))).
+func Distinct(expr SQLer) SQLer {
+	return distinct{
+		expr: expr,
+	}
+}
+
+func (d distinct) SQL() (string, []interface{}, error) {
+	return SQLDialect(d, Default)
+}
+
+func (d distinct) render(r *renderer) (string, []interface{}, error) {
+	sql, args, err := r.render(d.expr)
+	if err != nil {
+		return "", nil, err
+	}
+	return fmt.Sprintf("%s %s", r.keyword("DISTINCT"), sql), args, nil
+}
+
+type cast struct {
+	expr SQLer
+	kind SQLer
+}
+
+// Cast converts expr to kind, one of the values returned by the Type
+// functions. With MySQL, the integer types are written as SIGNED and the
+// character types as CHAR.
+func Cast(expr, kind SQLer) SQLer {
+	return cast{
+		expr: expr,
+		kind: kind,
+	}
+}
+
+func (c cast) SQL() (string, []interface{}, error) {
+	return SQLDialect(c, Default)
+}
+
+func (c cast) render(r *renderer) (string, []interface{}, error) {
+	kind, ok := c.kind.(columnType)
+	if !ok {
+		return "", nil, fmt.Errorf("cast: %w: invalid type", ErrSyntax)
+	}
+	sql, args, err := r.render(c.expr)
+	if err != nil {
+		return "", nil, err
+	}
+	var str string
+	switch {
+	case r.is(MySQL) && (kind.kind == typeInt || kind.kind == typeSmallInt || kind.kind == typeBigInt):
+		str = r.keyword("SIGNED")
+	case r.is(MySQL) && kind.kind == typeText:
+		str = r.keyword("CHAR")
+	case r.is(MySQL) && kind.kind == typeVarchar:
+		str, _, err = r.render(TypeChar(kind.args[0]))
+	default:
+		str, _, err = r.render(kind)
+	}
+	if err != nil {
+		return "", nil, err
+	}
+	return fmt.Sprintf("%s(%s %s %s)", r.keyword("CAST"), sql, r.keyword("AS"), str), args, nil
+}
+
 func Count(ident SQLer) SQLer {
 	return Func("COUNT", ident)
 }
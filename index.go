@@ -0,0 +1,264 @@
+package quel
+
+import (
+	"fmt"
+	"strings"
+)
+
+type CreateIndexOption func(*CreateIndex) error
+
+func IndexColumns(columns ...string) CreateIndexOption {
+	return func(i *CreateIndex) error {
+		for _, c := range columns {
+			if !isValidIdentifier(c) {
+				return fmt.Errorf("index: %w %q", ErrIdent, c)
+			}
+			i.columns = append(i.columns, NewIdent(c))
+		}
+		return nil
+	}
+}
+
+// IndexExpr adds an expression to the indexed values, eg LOWER(email).
+func IndexExpr(expr SQLer) CreateIndexOption {
+	return func(i *CreateIndex) error {
+		if expr == nil {
+			return fmt.Errorf("index: %w: no expression given", ErrSyntax)
+		}
+		i.columns = append(i.columns, indexExpr{inner: expr})
+		return nil
+	}
+}
+
+func IndexUnique() CreateIndexOption {
+	return func(i *CreateIndex) error {
+		i.unique = true
+		return nil
+	}
+}
+
+// IndexConcurrently builds the index without locking the table. It is only
+// supported by Postgres.
+func IndexConcurrently() CreateIndexOption {
+	return func(i *CreateIndex) error {
+		i.concurrently = true
+		return nil
+	}
+}
+
+// IndexIfNotExists is not supported by MySQL.
+func IndexIfNotExists() CreateIndexOption {
+	return func(i *CreateIndex) error {
+		i.exists = true
+		return nil
+	}
+}
+
+// IndexWhere makes the index partial: only the rows matching cdt are indexed.
+// It is not supported by MySQL.
+func IndexWhere(cdt SQLer) CreateIndexOption {
+	return func(i *CreateIndex) error {
+		if !acceptRelational(cdt) {
+			return fmt.Errorf("where: %w", ErrSyntax)
+		}
+		i.where = cdt
+		return nil
+	}
+}
+
+type CreateIndex struct {
+	name         string
+	table        SQLer
+	columns      []SQLer
+	where        SQLer
+	unique       bool
+	concurrently bool
+	exists       bool
+}
+
+func NewCreateIndex(name, table string, options ...CreateIndexOption) (CreateIndex, error) {
+	var (
+		i   CreateIndex
+		err error
+	)
+	i.name = name
+	i.table = NewIdent(table)
+	if !isValidIdentifier(name) {
+		return i, fmt.Errorf("index: %w %q", ErrIdent, name)
+	}
+	for _, opt := range options {
+		if err = opt(&i); err != nil {
+			break
+		}
+	}
+	if err == nil && len(i.columns) == 0 {
+		err = fmt.Errorf("%w: no columns given to be indexed", ErrSyntax)
+	}
+	return i, err
+}
+
+func (i CreateIndex) SQL() (string, []interface{}, error) {
+	return SQLDialect(i, Default)
+}
+
+func (i CreateIndex) SQLDialect(d Dialect) (string, []interface{}, error) {
+	return SQLDialect(i, d)
+}
+
+func (i CreateIndex) render(r *renderer) (string, []interface{}, error) {
+	switch {
+	case i.concurrently && !r.is(Postgres) && !r.is(Default):
+		return "", nil, fmt.Errorf("create index: concurrently only supported by Postgres")
+	case i.exists && r.is(MySQL):
+		return "", nil, fmt.Errorf("create index: if not exists not supported by MySQL")
+	case i.where != nil && r.is(MySQL):
+		return "", nil, fmt.Errorf("create index: where not supported by MySQL")
+	}
+	var b strings.Builder
+	b.WriteString(r.keyword("CREATE") + " ")
+	if i.unique {
+		b.WriteString(r.keyword("UNIQUE") + " ")
+	}
+	b.WriteString(r.keyword("INDEX") + " ")
+	if i.concurrently {
+		b.WriteString(r.keyword("CONCURRENTLY") + " ")
+	}
+	if i.exists {
+		b.WriteString(r.keyword("IF NOT EXISTS") + " ")
+	}
+	b.WriteString(r.ident(i.name))
+	b.WriteString(" " + r.keyword("ON") + " ")
+	sql, err := renderDDL(r, i.table)
+	if err != nil {
+		return "", nil, err
+	}
+	b.WriteString(sql)
+	b.WriteString(" (")
+	for j, c := range i.columns {
+		if j > 0 {
+			b.WriteString(", ")
+		}
+		sql, err := renderDDL(r, c)
+		if err != nil {
+			return "", nil, err
+		}
+		b.WriteString(sql)
+	}
+	b.WriteString(")")
+	if i.where != nil {
+		sql, err := renderDDL(r, i.where)
+		if err != nil {
+			return "", nil, err
+		}
+		b.WriteString(r.clause("WHERE"))
+		b.WriteString(sql)
+	}
+	return b.String(), nil, nil
+}
+
+type indexExpr struct {
+	inner SQLer
+}
+
+func (i indexExpr) SQL() (string, []interface{}, error) {
+	return SQLDialect(i, Default)
+}
+
+func (i indexExpr) render(r *renderer) (string, []interface{}, error) {
+	sql, args, err := r.render(i.inner)
+	if err != nil {
+		return "", nil, err
+	}
+	return "(" + sql + ")", args, nil
+}
+
+type DropIndexOption func(*DropIndex) error
+
+// DropIndexTable sets the table of the index. It is required by MySQL and
+// ignored by the other dialects.
+func DropIndexTable(table string) DropIndexOption {
+	return func(d *DropIndex) error {
+		if !isValidIdentifier(table) {
+			return fmt.Errorf("drop index: %w %q", ErrIdent, table)
+		}
+		d.table = NewIdent(table)
+		return nil
+	}
+}
+
+// DropIndexIfExists is not supported by MySQL.
+func DropIndexIfExists() DropIndexOption {
+	return func(d *DropIndex) error {
+		d.exists = true
+		return nil
+	}
+}
+
+// DropIndexConcurrently is only supported by Postgres.
+func DropIndexConcurrently() DropIndexOption {
+	return func(d *DropIndex) error {
+		d.concurrently = true
+		return nil
+	}
+}
+
+type DropIndex struct {
+	name         string
+	table        SQLer
+	exists       bool
+	concurrently bool
+}
+
+func NewDropIndex(name string, options ...DropIndexOption) (DropIndex, error) {
+	var (
+		d   DropIndex
+		err error
+	)
+	d.name = name
+	if !isValidIdentifier(name) {
+		return d, fmt.Errorf("drop index: %w %q", ErrIdent, name)
+	}
+	for _, opt := range options {
+		if err = opt(&d); err != nil {
+			break
+		}
+	}
+	return d, err
+}
+
+func (d DropIndex) SQL() (string, []interface{}, error) {
+	return SQLDialect(d, Default)
+}
+
+func (d DropIndex) SQLDialect(dialect Dialect) (string, []interface{}, error) {
+	return SQLDialect(d, dialect)
+}
+
+func (d DropIndex) render(r *renderer) (string, []interface{}, error) {
+	switch {
+	case d.concurrently && !r.is(Postgres) && !r.is(Default):
+		return "", nil, fmt.Errorf("drop index: concurrently only supported by Postgres")
+	case r.is(MySQL) && d.exists:
+		return "", nil, fmt.Errorf("drop index: if exists not supported by MySQL")
+	case r.is(MySQL) && d.table == nil:
+		return "", nil, fmt.Errorf("drop index: table required by MySQL")
+	}
+	var b strings.Builder
+	b.WriteString(r.keyword("DROP INDEX") + " ")
+	if d.concurrently {
+		b.WriteString(r.keyword("CONCURRENTLY") + " ")
+	}
+	if d.exists {
+		b.WriteString(r.keyword("IF EXISTS") + " ")
+	}
+	b.WriteString(r.ident(d.name))
+	if r.is(MySQL) {
+		sql, err := renderDDL(r, d.table)
+		if err != nil {
+			return "", nil, err
+		}
+		b.WriteString(" " + r.keyword("ON") + " ")
+		b.WriteString(sql)
+	}
+	return b.String(), nil, nil
+}
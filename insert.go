@@ -19,16 +19,116 @@ func InsertColumns(columns ...string) InsertOption {
 	}
 }
 
+// InsertColumn adds column, an identifier or a Column of a Table, to the
+// columns of the statement. Its qualifier is not written.
+func InsertColumn(column SQLer) InsertOption {
+	return func(i *Insert) error {
+		name, err := columnName(column)
+		if err == nil {
+			i.columns = append(i.columns, NewIdent(name))
+		}
+		return err
+	}
+}
+
 func InsertValues(values ...SQLer) InsertOption {
 	return func(i *Insert) error {
 		if len(values) == 0 {
 			return fmt.Errorf("values: no values given")
 		}
+		if i.query != nil {
+			return fmt.Errorf("values: %w: select already given", ErrSyntax)
+		}
 		i.values = append(i.values, values)
 		return nil
 	}
 }
 
+// InsertStruct sets the columns and adds the values to insert from v, a struct,
+// a pointer to a struct or a slice of them to insert multiple rows. The names
+// of the columns are given by the db tag of the fields (see ScanAll). The
+// values are given as arguments named after their column, suffixed by _ and the
+// index of their row if v has more than one row. Such a name never replaces an
+// argument of the query created with Arg: if it is already used, it is
+// suffixed by _ and a number when the query is rendered by SQLNamed or Compile.
+func InsertStruct(v interface{}, options ...StructOption) InsertOption {
+	return func(i *Insert) error {
+		columns, rows, err := structRows(v, options)
+		if err != nil {
+			return err
+		}
+		if err := i.setColumns(columns); err != nil {
+			return err
+		}
+		for j, r := range rows {
+			vs := make([]SQLer, len(r))
+			for k := range r {
+				name := columns[k]
+				if len(rows) > 1 {
+					name = fmt.Sprintf("%s_%d", name, j)
+				}
+				vs[k] = argValue(name, r[k])
+			}
+			i.values = append(i.values, vs)
+		}
+		return nil
+	}
+}
+
+// InsertMap sets the columns and adds the values to insert from the keys and
+// values of m. The columns are sorted by name. Values that are not SQLer are
+// given as arguments named after their column, as with InsertStruct.
+func InsertMap(m map[string]interface{}) InsertOption {
+	return func(i *Insert) error {
+		columns, values, err := mapRow(m)
+		if err != nil {
+			return err
+		}
+		if len(columns) == 0 {
+			return fmt.Errorf("values: no values given")
+		}
+		if err := i.setColumns(columns); err != nil {
+			return err
+		}
+		vs := make([]SQLer, len(values))
+		for k := range values {
+			vs[k] = argValue(columns[k], values[k])
+		}
+		i.values = append(i.values, vs)
+		return nil
+	}
+}
+
+// InsertSelect sets the query giving the rows to insert, a Select or the
+// union of selects created with Union or UnionAll. The number of columns
+// selected by q should match the number of columns given with InsertColumns.
+func InsertSelect(q SQLer) InsertOption {
+	return func(i *Insert) error {
+		if len(i.values) > 0 {
+			return fmt.Errorf("select: %w: values already given", ErrSyntax)
+		}
+		switch q.(type) {
+		case Select, union:
+		default:
+			return fmt.Errorf("select: %w: select or union expected", ErrSyntax)
+		}
+		i.query = q
+		return nil
+	}
+}
+
+// InsertWith adds a common table expression to the statement. See
+// SelectWith.
+func InsertWith(name string, query SQLer, columns ...SQLer) InsertOption {
+	return func(i *Insert) error {
+		c, err := newCte(name, query, columns)
+		if err == nil {
+			i.ctes = append(i.ctes, c)
+		}
+		return err
+	}
+}
+
 func InsertReturn(values ...SQLer) InsertOption {
 	return func(i *Insert) error {
 		i.returning = append(i.returning, values...)
@@ -37,9 +137,12 @@ func InsertReturn(values ...SQLer) InsertOption {
 }
 
 type Insert struct {
+	ctes      []SQLer
 	table     SQLer
 	columns   []SQLer
 	values    [][]SQLer
+	query     SQLer
+	conflict  SQLer
 	returning []SQLer
 }
 
@@ -54,33 +157,97 @@ func NewInsert(table string, options ...InsertOption) (Insert, error) {
 			break
 		}
 	}
-	if len(i.values) == 0 {
+	if err != nil {
+		return i, err
+	}
+	if len(i.values) == 0 && i.query == nil {
 		return i, fmt.Errorf("%w: no values given to be inserted", ErrSyntax)
 	}
-	return i, err
+	return i, i.checkQuery()
+}
+
+// checkQuery verifies that the query given with InsertSelect selects as many
+// columns as the ones of the insert. Queries selecting all the columns with *
+// are accepted as is.
+func (i Insert) checkQuery() error {
+	q, ok := i.query.(Select)
+	if u, isUnion := i.query.(union); isUnion {
+		q, ok = u.left.(Select)
+	}
+	if !ok || len(i.columns) == 0 {
+		return nil
+	}
+	if n := q.columnsCount(); n > 0 && n != len(i.columns) {
+		return fmt.Errorf("insert: %w: select returns %d columns, %d expected", ErrColumn, n, len(i.columns))
+	}
+	return nil
+}
+
+func (i *Insert) setColumns(columns []string) error {
+	if len(i.columns) == 0 {
+		for _, c := range columns {
+			i.columns = append(i.columns, NewIdent(c))
+		}
+		return nil
+	}
+	if len(i.columns) != len(columns) {
+		return fmt.Errorf("insert: values mismatched number of columns")
+	}
+	for j, c := range i.columns {
+		if id, ok := c.(ident); !ok || id.name != columns[j] {
+			return fmt.Errorf("insert: column %q mismatched", columns[j])
+		}
+	}
+	return nil
 }
 
 func (i Insert) SQL() (string, []interface{}, error) {
-	var (
-		b    strings.Builder
-		args []interface{}
-	)
-	b.WriteString("INSERT INTO ")
-	sql, _, err := i.table.SQL()
+	return SQLDialect(i, Default)
+}
+
+func (i Insert) SQLDialect(d Dialect) (string, []interface{}, error) {
+	return SQLDialect(i, d)
+}
+
+func (i Insert) render(r *renderer) (string, []interface{}, error) {
+	var b strings.Builder
+	args, err := writeCtes(r, &b, i.ctes)
+	if err != nil {
+		return "", nil, err
+	}
+	kw := "INSERT INTO"
+	if c, ok := i.conflict.(conflict); ok && r.is(MySQL) && c.ignore() {
+		kw = "INSERT IGNORE INTO"
+	}
+	b.WriteString(r.keyword(kw) + " ")
+	sql, _, err := r.render(i.table)
 	if err != nil {
 		return "", nil, err
 	}
 	b.WriteString(sql)
 	if len(i.columns) > 0 {
 		b.WriteString("(")
-		as, err := writeSQL(&b, i.columns...)
+		as, err := writeSQL(r, &b, i.columns...)
 		if err != nil {
 			return "", nil, err
 		}
 		args = append(args, as...)
 		b.WriteString(")")
 	}
-	b.WriteString(" VALUES ")
+	if i.query != nil {
+		if err := i.checkQuery(); err != nil {
+			return "", nil, err
+		}
+		sql, as, err := r.render(i.query)
+		if err != nil {
+			return "", nil, err
+		}
+		args = append(args, as...)
+		b.WriteString(r.sep())
+		b.WriteString(sql)
+	} else {
+		b.WriteString(r.clause("VALUES"))
+	}
 	for j, vs := range i.values {
 		if len(i.columns) > 0 && len(vs) != len(i.columns) {
 			return "", nil, fmt.Errorf("insert: values mismatched number of columns")
@@ -89,16 +256,27 @@ func (i Insert) SQL() (string, []interface{}, error) {
 			b.WriteString(", ")
 		}
 		b.WriteString("(")
-		as, err := writeSQL(&b, vs...)
+		as, err := writeSQL(r, &b, vs...)
 		if err != nil {
 			return "", nil, err
 		}
 		args = append(args, as...)
 		b.WriteString(")")
 	}
+	if i.conflict != nil {
+		sql, as, err := r.render(i.conflict)
+		if err != nil {
+			return "", nil, err
+		}
+		args = append(args, as...)
+		if sql != "" {
+			b.WriteString(r.sep())
+			b.WriteString(sql)
+		}
+	}
 	if i.returning != nil {
-		b.WriteString(" RETURNING ")
-		as, err := writeSQL(&b, i.returning...)
+		b.WriteString(r.clause("RETURNING"))
+		as, err := writeSQL(r, &b, i.returning...)
 		if err != nil {
 			return "", nil, err
 		}
@@ -1,9 +1,123 @@
 package quel
 
 import (
+	"database/sql"
+	"errors"
+	"reflect"
 	"testing"
 )
 
+func TestInsertStruct(t *testing.T) {
+	type user struct {
+		ID    int    `db:"id,pk"`
+		First string `db:"first"`
+		Last  string `db:"last"`
+		Email string `db:"email,omitempty"`
+	}
+	data := []struct {
+		Options []InsertOption
+		Want    string
+		Args    []interface{}
+	}{
+		{
+			Options: []InsertOption{
+				InsertStruct(user{First: "roger", Last: "lamotte"}, SkipKeys()),
+			},
+			Want: "INSERT INTO users(first, last) VALUES (?, ?)",
+			Args: []interface{}{"roger", "lamotte"},
+		},
+		{
+			Options: []InsertOption{
+				InsertStruct([]user{{ID: 1, First: "roger"}, {ID: 2, First: "pierre", Email: "pierre@localhost"}}, SkipZero()),
+			},
+			Want: "INSERT INTO users(id, first, email) VALUES (?, ?, ?), (?, ?, ?)",
+			Args: []interface{}{1, "roger", "", 2, "pierre", "pierre@localhost"},
+		},
+		{
+			Options: []InsertOption{
+				InsertMap(map[string]interface{}{"last": "lamotte", "first": "roger", "created": Now()}),
+			},
+			Want: "INSERT INTO users(created, first, last) VALUES (NOW(), ?, ?)",
+			Args: []interface{}{"roger", "lamotte"},
+		},
+	}
+	for _, d := range data {
+		q, err := NewInsert("users", d.Options...)
+		if err != nil {
+			t.Errorf("error creating query! %s", err)
+			continue
+		}
+		compareQueries(t, q, d.Want, d.Args)
+	}
+	q, _ := NewInsert("users", InsertStruct([]user{{ID: 1, First: "roger"}, {ID: 2, First: "pierre"}}, SkipZero()))
+	compareDialect(t, q, Postgres, `INSERT INTO "users"("id", "first") VALUES ($1, $2), ($3, $4)`, []interface{}{1, "roger", 2, "pierre"})
+
+	str, args, err := SQLNamed(q, SQLite)
+	if err != nil {
+		t.Fatalf("error building query! %s", err)
+	}
+	if want := `INSERT INTO "users"("id", "first") VALUES (:id_0, :first_0), (:id_1, :first_1)`; str != want {
+		t.Errorf("queries mismatched!")
+		t.Logf("\twant: %s", want)
+		t.Logf("\tgot:  %s", str)
+	}
+	named := []sql.NamedArg{sql.Named("id_0", 1), sql.Named("first_0", "roger"), sql.Named("id_1", 2), sql.Named("first_1", "pierre")}
+	if !reflect.DeepEqual(args, named) {
+		t.Errorf("arguments mismatched! want %v, got %v", named, args)
+	}
+
+	q, _ = NewInsert("users", InsertStruct(user{First: "roger", Last: "lamotte"}, SkipKeys()))
+	tpl, err := Compile(q, Postgres)
+	if err != nil {
+		t.Fatalf("error compiling query! %s", err)
+	}
+	if want := `INSERT INTO "users"("first", "last") VALUES ($1, $2)`; tpl.Query() != want {
+		t.Errorf("queries mismatched!")
+		t.Logf("\twant: %s", want)
+		t.Logf("\tgot:  %s", tpl.Query())
+	}
+	values, err := tpl.BindStruct(user{First: "pierre", Last: "dubois"})
+	if err != nil {
+		t.Fatalf("error binding struct! %s", err)
+	}
+	if want := []interface{}{"pierre", "dubois"}; !reflect.DeepEqual(values, want) {
+		t.Errorf("arguments mismatched! want %v, got %v", want, values)
+	}
+
+	cdt := NotEqual(NewIdent("first"), Arg("first", "old"))
+	q, err = NewInsert("users", InsertStruct(user{ID: 1, First: "roger"}, SkipZero()), InsertOnConflict(OnColumns("id"), DoUpdate(UpdateColumn("first", Excluded("first")), UpdateWhere(cdt))))
+	if err != nil {
+		t.Fatalf("error creating query! %s", err)
+	}
+	compareDialect(t, q, SQLite, `INSERT INTO "users"("id", "first") VALUES (?, ?) ON CONFLICT ("id") DO UPDATE SET "first" = EXCLUDED."first" WHERE "first" <> ?`, []interface{}{1, "roger", "old"})
+	str, args, err = SQLNamed(q, SQLite)
+	if err != nil {
+		t.Fatalf("error building query! %s", err)
+	}
+	if want := `INSERT INTO "users"("id", "first") VALUES (:id, :first_1) ON CONFLICT ("id") DO UPDATE SET "first" = EXCLUDED."first" WHERE "first" <> :first`; str != want {
+		t.Errorf("queries mismatched!")
+		t.Logf("\twant: %s", want)
+		t.Logf("\tgot:  %s", str)
+	}
+	named = []sql.NamedArg{sql.Named("id", 1), sql.Named("first_1", "roger"), sql.Named("first", "old")}
+	if !reflect.DeepEqual(args, named) {
+		t.Errorf("arguments mismatched! want %v, got %v", named, args)
+	}
+	if tpl, err = Compile(q, Postgres); err != nil {
+		t.Fatalf("error compiling query! %s", err)
+	}
+	if want := []string{"id", "first_1", "first"}; !reflect.DeepEqual(tpl.Params(), want) {
+		t.Errorf("parameters mismatched! want %v, got %v", want, tpl.Params())
+	}
+
+	if _, err := NewInsert("users", InsertStruct(nil)); !errors.Is(err, ErrArg) {
+		t.Errorf("expected ErrArg when giving a nil value, got %v", err)
+	}
+	if _, err := NewInsert("users", InsertColumns("last"), InsertStruct(user{First: "roger"}, SkipZero())); err == nil {
+		t.Errorf("expected error when columns mismatched")
+	}
+}
+
 func TestInsert(t *testing.T) {
 	data := []struct {
 		Options []InsertOption
@@ -46,3 +160,140 @@ func TestInsert(t *testing.T) {
 		compareQueries(t, q, d.Want, d.Args)
 	}
 }
+
+func TestInsertOnConflict(t *testing.T) {
+	values := []InsertOption{
+		InsertColumns("id", "name"),
+		InsertValues(Arg("id", 1), Arg("name", "roger")),
+	}
+	data := []struct {
+		Options []InsertOption
+		Dialect Dialect
+		Want    string
+	}{
+		{
+			Options: []InsertOption{
+				InsertOnConflict(nil, DoNothing()),
+			},
+			Dialect: Default,
+			Want:    "INSERT INTO users(id, name) VALUES (?, ?) ON CONFLICT DO NOTHING",
+		},
+		{
+			Options: []InsertOption{
+				InsertOnConflict(OnColumns("id"), DoUpdate(UpdateColumn("name", Excluded("name")))),
+				InsertReturn(NewIdent("id")),
+			},
+			Dialect: Postgres,
+			Want:    `INSERT INTO "users"("id", "name") VALUES ($1, $2) ON CONFLICT ("id") DO UPDATE SET "name" = EXCLUDED."name" RETURNING "id"`,
+		},
+		{
+			Options: []InsertOption{
+				InsertOnConflict(OnConstraint("users_pkey"), DoUpdate(UpdateColumn("name", Excluded("name")), UpdateWhere(NotEqual(NewIdent("name"), Excluded("name"))))),
+			},
+			Dialect: Postgres,
+			Want:    `INSERT INTO "users"("id", "name") VALUES ($1, $2) ON CONFLICT ON CONSTRAINT "users_pkey" DO UPDATE SET "name" = EXCLUDED."name" WHERE "name" <> EXCLUDED."name"`,
+		},
+		{
+			Options: []InsertOption{
+				InsertOnConflict(OnColumns("id"), DoUpdate(UpdateColumn("name", Excluded("name")))),
+			},
+			Dialect: MySQL,
+			Want:    "INSERT INTO `users`(`id`, `name`) VALUES (?, ?) ON DUPLICATE KEY UPDATE `name` = VALUES(`name`)",
+		},
+		{
+			Options: []InsertOption{
+				InsertOnConflict(OnColumns("id"), DoNothing()),
+			},
+			Dialect: MySQL,
+			Want:    "INSERT INTO `users`(`id`, `name`) VALUES (?, ?) ON DUPLICATE KEY UPDATE `id` = `id`",
+		},
+		{
+			Options: []InsertOption{
+				InsertOnConflict(nil, DoNothing()),
+			},
+			Dialect: MySQL,
+			Want:    "INSERT IGNORE INTO `users`(`id`, `name`) VALUES (?, ?)",
+		},
+	}
+	for _, d := range data {
+		q, err := NewInsert("users", append(values, d.Options...)...)
+		if err != nil {
+			t.Errorf("error creating query! %s", err)
+			continue
+		}
+		compareDialect(t, q, d.Dialect, d.Want, []interface{}{1, "roger"})
+	}
+
+	roles, _ := NewSelect("roles")
+	invalid := []InsertOption{
+		InsertOnConflict(nil, DoUpdate(UpdateColumn("name", Excluded("name")))),
+		InsertOnConflict(OnColumns("id"), DoUpdate()),
+		InsertOnConflict(OnColumns("id"), DoUpdate(UpdateColumn("name", Excluded("name")), UpdateReturn(NewIdent("id")))),
+		InsertOnConflict(OnColumns("id"), DoUpdate(UpdateColumn("name", Excluded("name")), UpdateFrom(NewIdent("x")))),
+		InsertOnConflict(OnColumns("id"), DoUpdate(UpdateColumn("name", Excluded("name")), UpdateWith("x", roles))),
+		InsertOnConflict(NewIdent("id"), DoNothing()),
+	}
+	for _, opt := range invalid {
+		if _, err := NewInsert("users", append(values, opt)...); err == nil {
+			t.Errorf("expected error with invalid conflict clause")
+		}
+	}
+	q, _ := NewInsert("users", append(values, InsertOnConflict(OnConstraint("users_pkey"), DoNothing()))...)
+	if _, _, err := SQLDialect(q, SQLite); err == nil {
+		t.Errorf("expected error with constraint target for SQLite")
+	}
+}
+
+func TestInsertSelect(t *testing.T) {
+	actives, _ := NewSelect("users", SelectColumns("id"), SelectWhere(Equal(NewIdent("active"), Arg("active", true))))
+	ids, _ := NewSelect("actives", SelectColumns("id"))
+	options := []SelectOption{
+		SelectWith("actives", actives, NewIdent("id")),
+		SelectColumns("id", "name"),
+		SelectWhere(In(NewIdent("id"), ids)),
+		SelectLimit(10),
+	}
+	q, err := NewSelect("users", options...)
+	if err != nil {
+		t.Fatalf("error creating query! %s", err)
+	}
+	i, err := NewInsert("archives", InsertColumns("id", "name"), InsertSelect(q))
+	if err != nil {
+		t.Fatalf("error creating query! %s", err)
+	}
+	want := "INSERT INTO archives(id, name) WITH actives(id) AS (SELECT id FROM users WHERE active = ?) SELECT id, name FROM users WHERE id IN (SELECT id FROM actives) LIMIT 10"
+	compareQueries(t, i, want, []interface{}{true})
+
+	all, _ := NewSelect("users")
+	i, err = NewInsert("archives", InsertSelect(all), InsertReturn(NewIdent("id")))
+	if err != nil {
+		t.Fatalf("error creating query! %s", err)
+	}
+	compareDialect(t, i, Postgres, `INSERT INTO "archives" SELECT * FROM "users" RETURNING "id"`, nil)
+
+	admins, _ := NewSelect("admins", SelectColumns("id", "name"))
+	u, err := Union(q, admins)
+	if err != nil {
+		t.Fatalf("error creating union! %s", err)
+	}
+	i, err = NewInsert("archives", InsertColumns("id", "name"), InsertSelect(u))
+	if err != nil {
+		t.Fatalf("error creating query! %s", err)
+	}
+	want = "INSERT INTO archives(id, name) WITH actives(id) AS (SELECT id FROM users WHERE active = ?) SELECT id, name FROM users WHERE id IN (SELECT id FROM actives) LIMIT 10 UNION SELECT id, name FROM admins"
+	compareQueries(t, i, want, []interface{}{true})
+
+	invalid := [][]InsertOption{
+		{InsertColumns("id"), InsertSelect(u)},
+		{InsertSelect(NewIdent("users"))},
+		{InsertSelect(q), InsertColumns("id")},
+		{InsertColumns("id"), InsertSelect(q)},
+		{InsertValues(Arg("id", 1)), InsertSelect(q)},
+		{InsertSelect(q), InsertValues(Arg("id", 1))},
+	}
+	for _, options := range invalid {
+		if _, err := NewInsert("archives", options...); err == nil {
+			t.Errorf("expected error when creating invalid insert")
+		}
+	}
+}
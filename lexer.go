@@ -0,0 +1,284 @@
+package quel
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	eof rune = -(iota + 1)
+	word
+	quoted
+	number
+	text
+	param
+	punct
+)
+
+type position struct {
+	line   int
+	column int
+}
+
+func (p position) String() string {
+	return fmt.Sprintf("%d:%d", p.line, p.column)
+}
+
+type token struct {
+	kind    rune
+	literal string
+	pos     position
+}
+
+func (t token) String() string {
+	switch t.kind {
+	case eof:
+		return "end of input"
+	case text:
+		return fmt.Sprintf("'%s'", t.literal)
+	default:
+		return t.literal
+	}
+}
+
+// ParseError reports a syntax error found by Parse with its position in the
+// input.
+type ParseError struct {
+	Line    int
+	Column  int
+	Message string
+}
+
+func (e ParseError) Error() string {
+	return fmt.Sprintf("%d:%d: %s", e.Line, e.Column, e.Message)
+}
+
+func (e ParseError) Unwrap() error {
+	return ErrSyntax
+}
+
+type lexer struct {
+	input string
+	pos   int
+	cur   position
+	// ident is the quote used by the dialect for identifiers. The other
+	// double quote and back quote are then used for strings.
+	ident rune
+}
+
+func lex(str string, d Dialect) ([]token, error) {
+	x := lexer{
+		input: str,
+		cur:   position{line: 1, column: 1},
+	}
+	if q := d.Quote("a"); q != "a" {
+		x.ident, _ = utf8.DecodeRuneInString(q)
+	}
+	var list []token
+	for {
+		tok, err := x.next()
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, tok)
+		if tok.kind == eof {
+			break
+		}
+	}
+	return list, nil
+}
+
+func (x *lexer) next() (token, error) {
+	if err := x.skipBlank(); err != nil {
+		return token{}, err
+	}
+	tok := token{
+		pos: x.cur,
+	}
+	c := x.peek()
+	switch {
+	case c == utf8.RuneError:
+		tok.kind = eof
+	case isLetter(c) || c == underscore:
+		tok.kind = word
+		tok.literal = x.take(isIdent)
+	case isDigit(c) || (c == dot && isDigit(x.peekAt(1))):
+		tok.kind = number
+		tok.literal = x.number()
+	case c == squote:
+		tok.kind = text
+		str, err := x.quoted(squote)
+		if err != nil {
+			return tok, err
+		}
+		tok.literal = str
+	case c == dquote || c == bquote:
+		str, err := x.quoted(c)
+		if err != nil {
+			return tok, err
+		}
+		tok.kind = quoted
+		tok.literal = string(c) + strings.ReplaceAll(str, string(c), string(c)+string(c)) + string(c)
+		if x.ident != 0 && x.ident != c {
+			tok.kind = text
+			tok.literal = str
+		}
+	case c == '?':
+		x.read()
+		tok.kind = param
+		tok.literal = "?" + x.take(isDigit)
+	case c == '$':
+		x.read()
+		tok.kind = param
+		tok.literal = "$" + x.take(isIdent)
+	case (c == ':' || c == '@') && isLetter(x.peekAt(1)):
+		x.read()
+		tok.kind = param
+		tok.literal = string(c) + x.take(isIdent)
+	default:
+		tok.kind = punct
+		tok.literal = x.operator()
+		if tok.literal == "" {
+			return tok, x.errorf(tok.pos, "unexpected character %q", c)
+		}
+	}
+	return tok, nil
+}
+
+var operators = []string{
+	"<>", "!=", "<=", ">=", "||",
+	"=", "<", ">", "+", "-", "*", "/", "%", "&", "|", "^",
+	"(", ")", ",", ".", ";",
+}
+
+func (x *lexer) operator() string {
+	for _, op := range operators {
+		if strings.HasPrefix(x.input[x.pos:], op) {
+			for range op {
+				x.read()
+			}
+			return op
+		}
+	}
+	return ""
+}
+
+func (x *lexer) number() string {
+	var (
+		b    strings.Builder
+		seen bool
+	)
+	for {
+		c := x.peek()
+		if c == dot && !seen {
+			seen = true
+		} else if !isDigit(c) {
+			break
+		}
+		b.WriteRune(x.read())
+	}
+	if c := x.peek(); c == 'e' || c == 'E' {
+		b.WriteRune(x.read())
+		if c := x.peek(); c == '+' || c == '-' {
+			b.WriteRune(x.read())
+		}
+		b.WriteString(x.take(isDigit))
+	}
+	return b.String()
+}
+
+func (x *lexer) quoted(quote rune) (string, error) {
+	var (
+		b   strings.Builder
+		pos = x.cur
+	)
+	x.read()
+	for {
+		c := x.read()
+		if c == utf8.RuneError {
+			return "", x.errorf(pos, "unterminated quoted string")
+		}
+		if c == quote {
+			if x.peek() != quote {
+				break
+			}
+			x.read()
+		}
+		b.WriteRune(c)
+	}
+	return b.String(), nil
+}
+
+func (x *lexer) skipBlank() error {
+	for {
+		switch c := x.peek(); {
+		case c == ' ' || c == '\t' || c == '\r' || c == '\n':
+			x.read()
+		case c == '-' && x.peekAt(1) == '-':
+			for c := x.peek(); c != '\n' && c != utf8.RuneError; c = x.peek() {
+				x.read()
+			}
+		case c == '/' && x.peekAt(1) == '*':
+			pos := x.cur
+			x.read()
+			x.read()
+			for !(x.peek() == '*' && x.peekAt(1) == '/') {
+				if x.read() == utf8.RuneError {
+					return x.errorf(pos, "unterminated comment")
+				}
+			}
+			x.read()
+			x.read()
+		default:
+			return nil
+		}
+	}
+}
+
+func (x *lexer) take(accept func(rune) bool) string {
+	var b strings.Builder
+	for c := x.peek(); c != utf8.RuneError && accept(c); c = x.peek() {
+		b.WriteRune(x.read())
+	}
+	return b.String()
+}
+
+func (x *lexer) peek() rune {
+	return x.peekAt(0)
+}
+
+func (x *lexer) peekAt(n int) rune {
+	pos := x.pos
+	for {
+		c, z := utf8.DecodeRuneInString(x.input[pos:])
+		if n == 0 || z == 0 {
+			return c
+		}
+		pos += z
+		n--
+	}
+}
+
+func (x *lexer) read() rune {
+	c, z := utf8.DecodeRuneInString(x.input[x.pos:])
+	if z == 0 {
+		return utf8.RuneError
+	}
+	x.pos += z
+	if c == '\n' {
+		x.cur.line++
+		x.cur.column = 1
+	} else {
+		x.cur.column++
+	}
+	return c
+}
+
+func (x *lexer) errorf(pos position, format string, args ...interface{}) error {
+	return ParseError{
+		Line:    pos.line,
+		Column:  pos.column,
+		Message: fmt.Sprintf(format, args...),
+	}
+}
@@ -0,0 +1,335 @@
+package quel
+
+import (
+	"fmt"
+	"strings"
+)
+
+type MergeOption func(*Merge) error
+
+// MergeUsing sets the source of the rows to merge and the condition used to
+// match them with the rows of the target. source is a table or an aliased
+// Select.
+func MergeUsing(source, cdt SQLer) MergeOption {
+	return func(m *Merge) error {
+		if !isSource(source) {
+			return fmt.Errorf("using: %w: invalid source", ErrSyntax)
+		}
+		if !acceptRelational(cdt) {
+			return fmt.Errorf("using: %w: invalid condition", ErrSyntax)
+		}
+		m.source = source
+		m.cdt = cdt
+		return nil
+	}
+}
+
+func MergeAlias(alias string) MergeOption {
+	return func(m *Merge) error {
+		if !isValidIdentifier(alias) {
+			return fmt.Errorf("alias: %w %q", ErrIdent, alias)
+		}
+		m.table = Alias(alias, m.table)
+		return nil
+	}
+}
+
+// MergeMatched adds a WHEN MATCHED branch. cdt is an extra predicate that
+// can be nil. action is created with MergeUpdate, MergeDelete or
+// MergeDoNothing.
+func MergeMatched(cdt, action SQLer) MergeOption {
+	return mergeWhen(true, cdt, action)
+}
+
+// MergeNotMatched adds a WHEN NOT MATCHED branch. cdt is an extra predicate
+// that can be nil. action is created with MergeInsert or MergeDoNothing.
+func MergeNotMatched(cdt, action SQLer) MergeOption {
+	return mergeWhen(false, cdt, action)
+}
+
+func mergeWhen(matched bool, cdt, action SQLer) MergeOption {
+	return func(m *Merge) error {
+		if cdt != nil && !acceptRelational(cdt) {
+			return fmt.Errorf("when: %w: invalid condition", ErrSyntax)
+		}
+		a, ok := action.(mergeAction)
+		if !ok {
+			return fmt.Errorf("when: %w: invalid action", ErrSyntax)
+		}
+		if a.err != nil {
+			return a.err
+		}
+		switch a.kind {
+		case mergeUpdate, mergeDelete:
+			if !matched {
+				return fmt.Errorf("when not matched: %w: only INSERT or DO NOTHING allowed", ErrSyntax)
+			}
+		case mergeInsert:
+			if matched {
+				return fmt.Errorf("when matched: %w: INSERT not allowed", ErrSyntax)
+			}
+		}
+		m.branches = append(m.branches, mergeBranch{
+			matched: matched,
+			cdt:     cdt,
+			action:  action,
+		})
+		return nil
+	}
+}
+
+func MergeReturn(values ...SQLer) MergeOption {
+	return func(m *Merge) error {
+		m.returning = append(m.returning, values...)
+		return nil
+	}
+}
+
+// Merge is the MERGE statement. It is not supported by MySQL and SQLite and
+// its RETURNING clause is only supported by Postgres.
+type Merge struct {
+	table     SQLer
+	source    SQLer
+	cdt       SQLer
+	branches  []SQLer
+	returning []SQLer
+}
+
+func NewMerge(table string, options ...MergeOption) (Merge, error) {
+	var (
+		m   Merge
+		err error
+	)
+	m.table = NewIdent(table)
+	for _, opt := range options {
+		if err = opt(&m); err != nil {
+			break
+		}
+	}
+	if err == nil && m.source == nil {
+		err = fmt.Errorf("%w: no source given to be merged", ErrSyntax)
+	}
+	if err == nil && len(m.branches) == 0 {
+		err = fmt.Errorf("%w: no WHEN clauses given", ErrSyntax)
+	}
+	return m, err
+}
+
+func (m Merge) SQL() (string, []interface{}, error) {
+	return SQLDialect(m, Default)
+}
+
+func (m Merge) SQLDialect(d Dialect) (string, []interface{}, error) {
+	return SQLDialect(m, d)
+}
+
+func (m Merge) render(r *renderer) (string, []interface{}, error) {
+	if r.is(MySQL) || r.is(SQLite) {
+		return "", nil, fmt.Errorf("merge: statement not supported by dialect")
+	}
+	var (
+		b    strings.Builder
+		args []interface{}
+	)
+	b.WriteString(r.keyword("MERGE INTO") + " ")
+	sql, _, err := r.render(m.table)
+	if err != nil {
+		return "", nil, err
+	}
+	b.WriteString(sql)
+
+	b.WriteString(r.clause("USING"))
+	sql, as, err := r.render(m.source)
+	if err != nil {
+		return "", nil, err
+	}
+	args = append(args, as...)
+	b.WriteString(sql)
+
+	sql, as, err = r.render(m.cdt)
+	if err != nil {
+		return "", nil, err
+	}
+	args = append(args, as...)
+	b.WriteString(" " + r.keyword("ON") + " ")
+	b.WriteString(sql)
+
+	for _, w := range m.branches {
+		sql, as, err := r.render(w)
+		if err != nil {
+			return "", nil, err
+		}
+		args = append(args, as...)
+		b.WriteString(r.sep())
+		b.WriteString(sql)
+	}
+	if m.returning != nil {
+		if !r.is(Postgres) && !r.is(Default) {
+			return "", nil, fmt.Errorf("merge: returning not supported by dialect")
+		}
+		b.WriteString(r.clause("RETURNING"))
+		as, err := writeSQL(r, &b, m.returning...)
+		if err != nil {
+			return "", nil, err
+		}
+		args = append(args, as...)
+	}
+	return b.String(), args, nil
+}
+
+type mergeBranch struct {
+	matched bool
+	cdt     SQLer
+	action  SQLer
+}
+
+func (w mergeBranch) SQL() (string, []interface{}, error) {
+	return SQLDialect(w, Default)
+}
+
+func (w mergeBranch) render(r *renderer) (string, []interface{}, error) {
+	var (
+		b    strings.Builder
+		args []interface{}
+	)
+	if w.matched {
+		b.WriteString(r.keyword("WHEN MATCHED"))
+	} else {
+		b.WriteString(r.keyword("WHEN NOT MATCHED"))
+	}
+	if w.cdt != nil {
+		sql, as, err := r.render(w.cdt)
+		if err != nil {
+			return "", nil, err
+		}
+		args = append(args, as...)
+		b.WriteString(" " + r.keyword("AND") + " ")
+		b.WriteString(sql)
+	}
+	sql, as, err := r.render(w.action)
+	if err != nil {
+		return "", nil, err
+	}
+	args = append(args, as...)
+	b.WriteString(" " + r.keyword("THEN") + " ")
+	b.WriteString(sql)
+	return b.String(), args, nil
+}
+
+const (
+	mergeNothing uint8 = iota
+	mergeUpdate
+	mergeDelete
+	mergeInsert
+)
+
+type mergeAction struct {
+	kind    uint8
+	columns []SQLer
+	values  []SQLer
+	err     error
+}
+
+// MergeUpdate returns the action updating the matched rows. Only the options
+// UpdateColumn, UpdateStruct and UpdateMap can be given.
+func MergeUpdate(options ...UpdateOption) SQLer {
+	var (
+		u Update
+		a = mergeAction{kind: mergeUpdate}
+	)
+	for _, opt := range options {
+		if a.err = opt(&u); a.err != nil {
+			return a
+		}
+	}
+	switch {
+	case len(u.columns) == 0:
+		a.err = fmt.Errorf("merge update: %w: no columns to update", ErrSyntax)
+	case u.table != nil || u.where != nil || len(u.returning) > 0 || len(u.sources) > 0 || len(u.ctes) > 0:
+		a.err = fmt.Errorf("merge update: %w: unsupported option", ErrSyntax)
+	}
+	a.columns = u.columns
+	return a
+}
+
+// MergeInsert returns the action inserting the rows that are not matched.
+// Only the options InsertColumns, InsertValues, InsertStruct and InsertMap can
+// be given and a single row of values is expected.
+func MergeInsert(options ...InsertOption) SQLer {
+	var (
+		i Insert
+		a = mergeAction{kind: mergeInsert}
+	)
+	for _, opt := range options {
+		if a.err = opt(&i); a.err != nil {
+			return a
+		}
+	}
+	switch {
+	case len(i.values) != 1:
+		a.err = fmt.Errorf("merge insert: %w: one row of values expected", ErrSyntax)
+	case len(i.columns) > 0 && len(i.columns) != len(i.values[0]):
+		a.err = fmt.Errorf("merge insert: values mismatched number of columns")
+	case i.table != nil || i.conflict != nil || len(i.returning) > 0 || len(i.ctes) > 0 || i.query != nil:
+		a.err = fmt.Errorf("merge insert: %w: unsupported option", ErrSyntax)
+	default:
+		a.columns, a.values = i.columns, i.values[0]
+	}
+	return a
+}
+
+// MergeDelete returns the action deleting the matched rows.
+func MergeDelete() SQLer {
+	return mergeAction{kind: mergeDelete}
+}
+
+// MergeDoNothing returns the action skipping the rows.
+func MergeDoNothing() SQLer {
+	return mergeAction{kind: mergeNothing}
+}
+
+func (a mergeAction) SQL() (string, []interface{}, error) {
+	return SQLDialect(a, Default)
+}
+
+func (a mergeAction) render(r *renderer) (string, []interface{}, error) {
+	if a.err != nil {
+		return "", nil, a.err
+	}
+	var (
+		b    strings.Builder
+		args []interface{}
+	)
+	switch a.kind {
+	case mergeNothing:
+		b.WriteString(r.keyword("DO NOTHING"))
+	case mergeDelete:
+		b.WriteString(r.keyword("DELETE"))
+	case mergeUpdate:
+		b.WriteString(r.keyword("UPDATE SET") + " ")
+		as, err := writeSQL(r, &b, a.columns...)
+		if err != nil {
+			return "", nil, err
+		}
+		args = append(args, as...)
+	case mergeInsert:
+		b.WriteString(r.keyword("INSERT"))
+		if len(a.columns) > 0 {
+			b.WriteString(" (")
+			if _, err := writeSQL(r, &b, a.columns...); err != nil {
+				return "", nil, err
+			}
+			b.WriteString(")")
+		}
+		b.WriteString(" " + r.keyword("VALUES") + " (")
+		as, err := writeSQL(r, &b, a.values...)
+		if err != nil {
+			return "", nil, err
+		}
+		args = append(args, as...)
+		b.WriteString(")")
+	default:
+		return "", nil, fmt.Errorf("merge: unsupported action")
+	}
+	return b.String(), args, nil
+}
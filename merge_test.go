@@ -0,0 +1,80 @@
+package quel
+
+import (
+	"testing"
+)
+
+func TestMerge(t *testing.T) {
+	staging, _ := NewSelect("staging", SelectColumns("id", "name", "deleted"), SelectWhere(Equal(NewIdent("batch"), Arg("batch", 7))))
+	data := []struct {
+		Options []MergeOption
+		Dialect Dialect
+		Want    string
+		Args    []interface{}
+	}{
+		{
+			Options: []MergeOption{
+				MergeAlias("u"),
+				MergeUsing(Alias("s", NewIdent("staging")), Equal(NewIdent("id", "u"), NewIdent("id", "s"))),
+				MergeMatched(nil, MergeUpdate(UpdateColumn("name", NewIdent("name", "s")))),
+				MergeNotMatched(nil, MergeInsert(InsertColumns("id", "name"), InsertValues(NewIdent("id", "s"), NewIdent("name", "s")))),
+			},
+			Dialect: Default,
+			Want:    "MERGE INTO users AS u USING staging AS s ON u.id = s.id WHEN MATCHED THEN UPDATE SET name = s.name WHEN NOT MATCHED THEN INSERT (id, name) VALUES (s.id, s.name)",
+		},
+		{
+			Options: []MergeOption{
+				MergeUsing(Alias("s", staging), Equal(NewIdent("id", "users"), NewIdent("id", "s"))),
+				MergeMatched(Equal(NewIdent("deleted", "s"), Arg("deleted", true)), MergeDelete()),
+				MergeMatched(nil, MergeUpdate(UpdateColumn("name", NewIdent("name", "s")))),
+				MergeNotMatched(Equal(NewIdent("deleted", "s"), Arg("deleted", true)), MergeDoNothing()),
+				MergeNotMatched(nil, MergeInsert(InsertValues(NewIdent("id", "s"), NewIdent("name", "s")))),
+				MergeReturn(NewIdent("id", "users")),
+			},
+			Dialect: Postgres,
+			Want:    `MERGE INTO "users" USING (SELECT "id", "name", "deleted" FROM "staging" WHERE "batch" = $1) AS "s" ON "users"."id" = "s"."id" WHEN MATCHED AND "s"."deleted" = $2 THEN DELETE WHEN MATCHED THEN UPDATE SET "name" = "s"."name" WHEN NOT MATCHED AND "s"."deleted" = $3 THEN DO NOTHING WHEN NOT MATCHED THEN INSERT VALUES ("s"."id", "s"."name") RETURNING "users"."id"`,
+			Args:    []interface{}{7, true, true},
+		},
+	}
+	for _, d := range data {
+		q, err := NewMerge("users", d.Options...)
+		if err != nil {
+			t.Errorf("error creating query! %s", err)
+			continue
+		}
+		compareDialect(t, q, d.Dialect, d.Want, d.Args)
+	}
+
+	using := MergeUsing(NewIdent("staging"), Equal(NewIdent("id", "users"), NewIdent("id", "staging")))
+	invalid := [][]MergeOption{
+		{MergeMatched(nil, MergeDelete())},
+		{using},
+		{using, MergeMatched(nil, MergeInsert(InsertValues(NewIdent("id"))))},
+		{using, MergeNotMatched(nil, MergeDelete())},
+		{using, MergeMatched(nil, MergeUpdate())},
+		{using, MergeMatched(nil, MergeUpdate(UpdateColumn("name", NewIdent("name")), UpdateWhere(IsNullTest(NewIdent("name")))))},
+		{using, MergeNotMatched(nil, MergeInsert(InsertColumns("id", "name"), InsertValues(NewIdent("id"))))},
+		{using, MergeMatched(nil, MergeUpdate(UpdateColumn("name", NewIdent("name")), UpdateFrom(NewIdent("roles"))))},
+		{using, MergeMatched(nil, MergeUpdate(UpdateColumn("name", NewIdent("name")), UpdateJoin(NewIdent("roles"), Equal(NewIdent("role"), NewIdent("id", "roles")))))},
+		{using, MergeMatched(nil, MergeUpdate(UpdateColumn("name", NewIdent("name")), UpdateWith("roles", staging)))},
+		{using, MergeNotMatched(nil, MergeInsert(InsertWith("roles", staging), InsertColumns("id"), InsertValues(NewIdent("id"))))},
+		{MergeUsing(NewLiteral(1), Equal(NewIdent("id"), NewIdent("id")))},
+		{MergeUsing(staging, Equal(NewIdent("id", "users"), NewIdent("id", "staging"))), MergeMatched(nil, MergeDelete())},
+	}
+	for _, options := range invalid {
+		if _, err := NewMerge("users", options...); err == nil {
+			t.Errorf("expected error when creating invalid merge")
+		}
+	}
+
+	q, _ := NewMerge("users", using, MergeMatched(nil, MergeDelete()))
+	for _, d := range []Dialect{MySQL, SQLite} {
+		if _, _, err := SQLDialect(q, d); err == nil {
+			t.Errorf("expected error when rendering merge for unsupported dialect")
+		}
+	}
+	q, _ = NewMerge("users", using, MergeMatched(nil, MergeDelete()), MergeReturn(NewIdent("id")))
+	if _, _, err := SQLDialect(q, ANSI); err == nil {
+		t.Errorf("expected error when rendering returning for unsupported dialect")
+	}
+}
@@ -0,0 +1,1920 @@
+package quel
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Parse parses a SELECT, INSERT, UPDATE, DELETE, CREATE TABLE, CREATE INDEX or
+// CREATE VIEW statement written for the given dialect and returns the SQLer
+// that renders it. Placeholders have no value and are returned as nil with
+// the rendered query: a placeholder written as :name, @name or $name is called
+// name, a placeholder written as ? has no name.
+//
+// The statements are limited to what the builders of the package can
+// express: for example, a SELECT can have no FROM clause but set operations
+// other than UNION, like INTERSECT and EXCEPT, are not supported.
+//
+// Errors are reported as ParseError wrapping ErrSyntax.
+func Parse(str string, d Dialect) (SQLer, error) {
+	if d == nil {
+		d = Default
+	}
+	tokens, err := lex(str, d)
+	if err != nil {
+		return nil, err
+	}
+	p := parser{
+		tokens: tokens,
+	}
+	q, err := p.parseStatement()
+	if err != nil {
+		return nil, err
+	}
+	p.accept(";")
+	if !p.done() {
+		return nil, p.unexpected()
+	}
+	return q, nil
+}
+
+// ParseScript parses a sequence of statements separated by semicolons, eg
+// the dump of the schema of a database. See Parse for the supported
+// statements.
+func ParseScript(str string, d Dialect) ([]SQLer, error) {
+	if d == nil {
+		d = Default
+	}
+	tokens, err := lex(str, d)
+	if err != nil {
+		return nil, err
+	}
+	p := parser{
+		tokens: tokens,
+	}
+	var list []SQLer
+	for !p.done() {
+		if p.accept(";") {
+			continue
+		}
+		q, err := p.parseStatement()
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, q)
+		if !p.accept(";") && !p.done() {
+			return nil, p.unexpected()
+		}
+	}
+	return list, nil
+}
+
+type parser struct {
+	tokens []token
+	pos    int
+}
+
+func (p *parser) parseStatement() (SQLer, error) {
+	switch {
+	case p.is("WITH"):
+		return p.parseWith()
+	case p.is("SELECT"):
+		return p.parseQuery()
+	case p.is("INSERT"):
+		return p.parseInsert()
+	case p.is("UPDATE"):
+		return p.parseUpdate()
+	case p.is("DELETE"):
+		return p.parseDelete()
+	case p.is("CREATE"):
+		return p.parseCreate()
+	default:
+		return nil, p.unexpected()
+	}
+}
+
+// parseWith parses the common table expressions of a statement. The tokens
+// are parsed again by parseSelect if the statement is a query.
+func (p *parser) parseWith() (SQLer, error) {
+	start := p.pos
+	p.next()
+	ctes, err := p.parseCtes()
+	if err != nil {
+		return nil, err
+	}
+	if !p.is("INSERT") && !p.is("UPDATE") && !p.is("DELETE") {
+		p.pos = start
+		return p.parseQuery()
+	}
+	q, err := p.parseStatement()
+	if err != nil {
+		return nil, err
+	}
+	switch s := q.(type) {
+	case Insert:
+		s.ctes = ctes
+		q = s
+	case Update:
+		s.ctes = ctes
+		q = s
+	case Delete:
+		s.ctes = ctes
+		q = s
+	}
+	return q, nil
+}
+
+func (p *parser) parseQuery() (SQLer, error) {
+	q, err := p.parseSelect()
+	if err != nil {
+		return nil, err
+	}
+	var left SQLer = q
+	for p.accept("UNION") {
+		all := p.accept("ALL")
+		right, err := p.parseSelect()
+		if err != nil {
+			return nil, err
+		}
+		pos := p.peek().pos
+		if all {
+			left, err = newUnion(left, right, true)
+		} else {
+			left, err = newUnion(left, right, false)
+		}
+		if err != nil {
+			return nil, p.errorAt(pos, "%s", err)
+		}
+	}
+	return left, nil
+}
+
+func (p *parser) parseSelect() (Select, error) {
+	var (
+		q   Select
+		err error
+	)
+	if p.accept("WITH") {
+		if q.ctes, err = p.parseCtes(); err != nil {
+			return q, err
+		}
+	}
+	if err := p.expect("SELECT"); err != nil {
+		return q, err
+	}
+	q.distinct = p.accept("DISTINCT")
+
+	var columns []SQLer
+	for {
+		if p.accept("*") {
+			columns = append(columns, NewIdent("*"))
+		} else {
+			col, err := p.parseAliased()
+			if err != nil {
+				return q, err
+			}
+			columns = append(columns, col)
+		}
+		if !p.accept(",") {
+			break
+		}
+	}
+	if len(columns) == 1 {
+		if i, ok := columns[0].(ident); ok && i.name == "*" && len(i.parents) == 0 {
+			columns = nil
+		}
+	}
+	q.queries = append(q.queries, query{columns: columns})
+	if p.accept("FROM") {
+		if err := p.parseFrom(&q); err != nil {
+			return q, err
+		}
+	}
+	if p.accept("WHERE") {
+		if q.where, err = p.parsePredicate(); err != nil {
+			return q, err
+		}
+	}
+	if p.accept("GROUP") {
+		if err := p.expect("BY"); err != nil {
+			return q, err
+		}
+		if q.groupby, err = p.parseList(p.parseExpr); err != nil {
+			return q, err
+		}
+		if p.accept("HAVING") {
+			if q.having, err = p.parsePredicate(); err != nil {
+				return q, err
+			}
+		}
+	}
+	if p.accept("WINDOW") {
+		for {
+			pos := p.peek().pos
+			name, err := p.parseName()
+			if err != nil {
+				return q, err
+			}
+			if err := p.expect("AS"); err != nil {
+				return q, err
+			}
+			var w window
+			if err := p.parseWindowSpec(&w); err != nil {
+				return q, err
+			}
+			if err := SelectWindow(name, w)(&q); err != nil {
+				return q, p.errorAt(pos, "%s", err)
+			}
+			if !p.accept(",") {
+				break
+			}
+		}
+	}
+	if p.accept("ORDER") {
+		if err := p.expect("BY"); err != nil {
+			return q, err
+		}
+		if q.orderby, err = p.parseList(p.parseOrder); err != nil {
+			return q, err
+		}
+	}
+	return q, p.parsePagination(&q)
+}
+
+// parseFrom parses the sources of the FROM clause of q and their joins.
+func (p *parser) parseFrom(q *Select) error {
+	source, err := p.parseSource()
+	if err != nil {
+		return err
+	}
+	q.queries[0].table = source
+	for {
+		if p.accept(",") {
+			source, err := p.parseSource()
+			if err != nil {
+				return err
+			}
+			q.queries = append(q.queries, query{table: source})
+			continue
+		}
+		jt, ok := p.parseJoinType()
+		if !ok {
+			break
+		}
+		pos := p.peek().pos
+		source, err := p.parseSource()
+		if err != nil {
+			return err
+		}
+		if !isJoinable(source) {
+			return p.errorAt(pos, "source can not be joined")
+		}
+		var cdt SQLer
+		if p.accept("USING") {
+			list, err := p.parseParenList(p.parseIdent)
+			if err != nil {
+				return err
+			}
+			cdt = Using(list...)
+		} else {
+			if err := p.expect("ON"); err != nil {
+				return err
+			}
+			pos := p.peek().pos
+			if cdt, err = p.parseExpr(); err != nil {
+				return err
+			}
+			if !acceptRelational(cdt) {
+				return p.errorAt(pos, "invalid join condition")
+			}
+		}
+		q.queries = append(q.queries, query{
+			table: source,
+			cdt:   cdt,
+			join:  jt,
+		})
+	}
+	return nil
+}
+
+func (p *parser) parseCtes() ([]SQLer, error) {
+	var (
+		list      []SQLer
+		recursive = p.accept("RECURSIVE")
+	)
+	for {
+		pos := p.peek().pos
+		name, err := p.parseName()
+		if err != nil {
+			return nil, err
+		}
+		if !isValidIdentifier(name) {
+			return nil, p.errorAt(pos, "invalid identifier %q", name)
+		}
+		c := cte{
+			name:      name,
+			recursive: recursive,
+		}
+		if p.is("(") {
+			if c.columns, err = p.parseParenList(p.parseIdent); err != nil {
+				return nil, err
+			}
+		}
+		if err := p.expect("AS"); err != nil {
+			return nil, err
+		}
+		switch {
+		case p.accept("MATERIALIZED"):
+			c.materialize = materializeAlways
+		case p.accept("NOT"):
+			if err := p.expect("MATERIALIZED"); err != nil {
+				return nil, err
+			}
+			c.materialize = materializeNever
+		}
+		if err := p.expect("("); err != nil {
+			return nil, err
+		}
+		if p.is("INSERT") || p.is("UPDATE") || p.is("DELETE") {
+			c.inner, err = p.parseStatement()
+		} else {
+			c.inner, err = p.parseQuery()
+		}
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expect(")"); err != nil {
+			return nil, err
+		}
+		list = append(list, c)
+		if !p.accept(",") {
+			break
+		}
+	}
+	return list, nil
+}
+
+func (p *parser) parseJoinType() (jointype, bool) {
+	switch {
+	case p.accept("JOIN"):
+		return innerLeft, true
+	case p.accept("INNER"):
+		return innerLeft, p.expect("JOIN") == nil
+	case p.accept("LEFT"):
+		p.accept("OUTER")
+		return outerLeft, p.expect("JOIN") == nil
+	case p.accept("RIGHT"):
+		jt := outerRight
+		if p.accept("INNER") {
+			jt = innerRight
+		} else {
+			p.accept("OUTER")
+		}
+		return jt, p.expect("JOIN") == nil
+	default:
+		return none, false
+	}
+}
+
+func (p *parser) parsePagination(q *Select) error {
+	var err error
+	if p.accept("LIMIT") {
+		if q.limit, err = p.parseCount(); err != nil {
+			return err
+		}
+	}
+	if p.accept("OFFSET") {
+		if q.offset, err = p.parseCount(); err != nil {
+			return err
+		}
+		if p.accept("ROWS") || p.accept("ROW") {
+			if p.accept("FETCH") {
+				if !p.accept("FIRST") {
+					if err := p.expect("NEXT"); err != nil {
+						return err
+					}
+				}
+				if q.limit, err = p.parseCount(); err != nil {
+					return err
+				}
+				if !p.accept("ROWS") {
+					if err := p.expect("ROW"); err != nil {
+						return err
+					}
+				}
+				return p.expect("ONLY")
+			}
+		}
+	}
+	return nil
+}
+
+func (p *parser) parseCount() (int, error) {
+	tok := p.peek()
+	if tok.kind != number {
+		return 0, p.unexpected()
+	}
+	p.next()
+	n, err := strconv.Atoi(tok.literal)
+	if err != nil || n < 0 {
+		return 0, p.errorAt(tok.pos, "invalid count %s", tok.literal)
+	}
+	return n, nil
+}
+
+// parseSource parses a table or a subquery of a FROM clause. A subquery
+// should be aliased.
+func (p *parser) parseSource() (SQLer, error) {
+	var (
+		source SQLer
+		err    error
+		pos    = p.peek().pos
+	)
+	if p.accept("(") {
+		if source, err = p.parseQuery(); err != nil {
+			return nil, err
+		}
+		if err := p.expect(")"); err != nil {
+			return nil, err
+		}
+	} else if source, err = p.parseIdent(); err != nil {
+		return nil, err
+	}
+	name, ok, err := p.parseAlias()
+	if err != nil {
+		return nil, err
+	}
+	if ok {
+		return Alias(name, source), nil
+	}
+	if isStatement(source) {
+		return nil, p.errorAt(pos, "subquery should be aliased")
+	}
+	return source, nil
+}
+
+func (p *parser) parseAliased() (SQLer, error) {
+	expr, err := p.parseExpr()
+	if err != nil {
+		return nil, err
+	}
+	if name, ok, err := p.parseAlias(); err != nil {
+		return nil, err
+	} else if ok {
+		expr = Alias(name, expr)
+	}
+	return expr, nil
+}
+
+func (p *parser) parseAlias() (string, bool, error) {
+	if p.accept("AS") {
+		name, err := p.parseName()
+		return name, err == nil, err
+	}
+	if tok := p.peek(); tok.kind == quoted || (tok.kind == word && !isReserved(tok.literal)) {
+		name, err := p.parseName()
+		return name, err == nil, err
+	}
+	return "", false, nil
+}
+
+// parseOrder parses a sort key of an ORDER BY clause. Columns are given to
+// Asc and Desc, the other expressions are kept as is.
+func (p *parser) parseOrder() (SQLer, error) {
+	pos := p.peek().pos
+	expr, err := p.parseExpr()
+	if err != nil {
+		return nil, err
+	}
+	order := "ASC"
+	if p.accept("DESC") {
+		order = "DESC"
+	} else {
+		p.accept("ASC")
+	}
+	if _, ok := expr.(ident); !ok {
+		return orderExpr(expr, order), nil
+	}
+	str, _, err := expr.SQL()
+	if err != nil {
+		return nil, p.errorAt(pos, "%s", err)
+	}
+	if order == "DESC" {
+		return Desc(str), nil
+	}
+	return Asc(str), nil
+}
+
+func (p *parser) parseInsert() (SQLer, error) {
+	p.next()
+	if err := p.expect("INTO"); err != nil {
+		return nil, err
+	}
+	var (
+		i   Insert
+		err error
+	)
+	if i.table, err = p.parseIdent(); err != nil {
+		return nil, err
+	}
+	if p.is("(") {
+		if i.columns, err = p.parseParenList(p.parseIdent); err != nil {
+			return nil, err
+		}
+	}
+	if p.is("SELECT") || p.is("WITH") {
+		pos := p.peek().pos
+		q, err := p.parseQuery()
+		if err != nil {
+			return nil, err
+		}
+		i.query = q
+		if err := i.checkQuery(); err != nil {
+			return nil, p.errorAt(pos, "%s", err)
+		}
+	} else if err := p.expect("VALUES"); err != nil {
+		return nil, err
+	}
+	for i.query == nil {
+		pos := p.peek().pos
+		values, err := p.parseParenList(p.parseExpr)
+		if err != nil {
+			return nil, err
+		}
+		if len(i.columns) > 0 && len(values) != len(i.columns) {
+			return nil, p.errorAt(pos, "values mismatched number of columns")
+		}
+		i.values = append(i.values, values)
+		if !p.accept(",") {
+			break
+		}
+	}
+	if p.accept("RETURNING") {
+		if i.returning, err = p.parseList(p.parseAliased); err != nil {
+			return nil, err
+		}
+	}
+	return i, nil
+}
+
+func (p *parser) parseUpdate() (SQLer, error) {
+	p.next()
+	var (
+		u   Update
+		err error
+	)
+	if u.table, err = p.parseIdent(); err != nil {
+		return nil, err
+	}
+	if name, ok, err := p.parseAlias(); err != nil {
+		return nil, err
+	} else if ok {
+		u.table = Alias(name, u.table)
+	}
+	if err := p.expect("SET"); err != nil {
+		return nil, err
+	}
+	for {
+		column, err := p.parseIdent()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expect("="); err != nil {
+			return nil, err
+		}
+		value, err := p.parseExpr()
+		if err != nil {
+			return nil, err
+		}
+		u.columns = append(u.columns, Equal(column, value))
+		if !p.accept(",") {
+			break
+		}
+	}
+	if p.accept("WHERE") {
+		if u.where, err = p.parsePredicate(); err != nil {
+			return nil, err
+		}
+	}
+	if p.accept("RETURNING") {
+		if u.returning, err = p.parseList(p.parseAliased); err != nil {
+			return nil, err
+		}
+	}
+	return u, nil
+}
+
+func (p *parser) parseDelete() (SQLer, error) {
+	p.next()
+	if err := p.expect("FROM"); err != nil {
+		return nil, err
+	}
+	var (
+		d   Delete
+		err error
+	)
+	if d.table, err = p.parseIdent(); err != nil {
+		return nil, err
+	}
+	if name, ok, err := p.parseAlias(); err != nil {
+		return nil, err
+	} else if ok {
+		d.table = Alias(name, d.table)
+	}
+	if p.accept("WHERE") {
+		if d.where, err = p.parsePredicate(); err != nil {
+			return nil, err
+		}
+	}
+	if p.accept("RETURNING") {
+		if d.returning, err = p.parseList(p.parseAliased); err != nil {
+			return nil, err
+		}
+	}
+	return d, nil
+}
+
+func (p *parser) parseCreate() (SQLer, error) {
+	p.next()
+	switch {
+	case p.is("TABLE"):
+		return p.parseCreateTable()
+	case p.is("UNIQUE") || p.is("INDEX"):
+		return p.parseCreateIndex()
+	case p.is("OR") || p.is("MATERIALIZED") || p.is("VIEW"):
+		return p.parseCreateView()
+	default:
+		return nil, p.unexpected()
+	}
+}
+
+// parseCreateTable parses a CREATE TABLE statement. The options given after
+// the definitions of the columns, eg WITHOUT ROWID or ENGINE=InnoDB, are
+// ignored.
+func (p *parser) parseCreateTable() (SQLer, error) {
+	p.next()
+	var (
+		c   CreateTable
+		err error
+	)
+	if c.exists, err = p.parseIfNotExists(); err != nil {
+		return nil, err
+	}
+	pos := p.peek().pos
+	name, err := p.parseName()
+	if err != nil {
+		return nil, err
+	}
+	if !isValidIdentifier(name) {
+		return nil, p.errorAt(pos, "invalid identifier %q", name)
+	}
+	c.table = NewIdent(name)
+	if p.accept("AS") {
+		q, err := p.parseSelect()
+		if err != nil {
+			return nil, err
+		}
+		c.query = q
+		return c, nil
+	}
+	if err := p.expect("("); err != nil {
+		return nil, err
+	}
+	for {
+		var (
+			pos = p.peek().pos
+			opt CreateTableOption
+			err error
+		)
+		if p.is("CONSTRAINT") || p.is("PRIMARY") || p.is("UNIQUE") || p.is("CHECK") || p.is("FOREIGN") {
+			opt, err = p.parseTableConstraint()
+		} else {
+			opt, err = p.parseColumnDef()
+		}
+		if err != nil {
+			return nil, err
+		}
+		if err := opt(&c); err != nil {
+			return nil, p.errorAt(pos, "%s", err)
+		}
+		if !p.accept(",") {
+			break
+		}
+	}
+	if err := p.expect(")"); err != nil {
+		return nil, err
+	}
+	if len(c.columns) == 0 {
+		return nil, p.errorAt(pos, "no columns given")
+	}
+	for !p.is(";") && !p.done() {
+		p.next()
+	}
+	return c, nil
+}
+
+func (p *parser) parseColumnDef() (CreateTableOption, error) {
+	name, err := p.parseName()
+	if err != nil {
+		return nil, err
+	}
+	kind, err := p.parseType()
+	if err != nil {
+		return nil, err
+	}
+	var options []ColumnOption
+	for {
+		switch {
+		case p.accept("CONSTRAINT"):
+			if _, err := p.parseName(); err != nil {
+				return nil, err
+			}
+		case p.accept("NOT"):
+			if err := p.expect("NULL"); err != nil {
+				return nil, err
+			}
+			options = append(options, ColumnNotNull())
+		case p.accept("NULL"):
+			options = append(options, ColumnNull())
+		case p.accept("DEFAULT"):
+			value, err := p.parseUnary()
+			if err != nil {
+				return nil, err
+			}
+			options = append(options, ColumnDefault(value))
+		case p.accept("PRIMARY"):
+			if err := p.expect("KEY"); err != nil {
+				return nil, err
+			}
+			if !p.accept("ASC") {
+				p.accept("DESC")
+			}
+			options = append(options, ColumnPrimaryKey())
+		case p.accept("AUTOINCREMENT") || p.accept("AUTO_INCREMENT"):
+			options = append(options, ColumnAutoIncrement())
+		case p.accept("GENERATED"):
+			if !p.accept("ALWAYS") {
+				if err := p.expect("BY"); err != nil {
+					return nil, err
+				}
+				if err := p.expect("DEFAULT"); err != nil {
+					return nil, err
+				}
+			}
+			if err := p.expect("AS"); err != nil {
+				return nil, err
+			}
+			if err := p.expect("IDENTITY"); err != nil {
+				return nil, err
+			}
+			options = append(options, ColumnAutoIncrement())
+		case p.accept("UNIQUE"):
+			options = append(options, ColumnUnique())
+		case p.accept("CHECK"):
+			cdt, err := p.parseCheck()
+			if err != nil {
+				return nil, err
+			}
+			options = append(options, ColumnCheck(cdt))
+		case p.accept("REFERENCES"):
+			pos := p.peek().pos
+			table, refs, opts, err := p.parseReferences()
+			if err != nil {
+				return nil, err
+			}
+			if len(refs) > 1 {
+				return nil, p.errorAt(pos, "too many referenced columns")
+			}
+			var column string
+			if len(refs) == 1 {
+				column = refs[0]
+			}
+			options = append(options, ColumnReferences(table, column, opts...))
+		case p.accept("COLLATE"):
+			if _, err := p.parseName(); err != nil {
+				return nil, err
+			}
+		default:
+			return CreateColumn(name, kind, options...), nil
+		}
+	}
+}
+
+var columnKeywords = []string{
+	"AUTOINCREMENT",
+	"AUTO_INCREMENT",
+	"CHECK",
+	"COLLATE",
+	"CONSTRAINT",
+	"DEFAULT",
+	"GENERATED",
+	"NOT",
+	"NULL",
+	"PRIMARY",
+	"REFERENCES",
+	"UNIQUE",
+}
+
+// parseType parses the type of a column. The types known by quel are given
+// by the Type functions, the others are kept as is with TypeRaw.
+func (p *parser) parseType() (SQLer, error) {
+	var words []string
+	for tok := p.peek(); tok.kind == word; tok = p.peek() {
+		if p.isOneOf(columnKeywords) {
+			break
+		}
+		words = append(words, strings.ToUpper(p.next().literal))
+	}
+	if len(words) == 0 {
+		return nil, p.unexpected()
+	}
+	var args []int
+	if p.accept("(") {
+		for {
+			n, err := p.parseCount()
+			if err != nil {
+				return nil, err
+			}
+			args = append(args, n)
+			if !p.accept(",") {
+				break
+			}
+		}
+		if err := p.expect(")"); err != nil {
+			return nil, err
+		}
+	}
+	return typeFromName(strings.Join(words, " "), args), nil
+}
+
+func (p *parser) isOneOf(list []string) bool {
+	for _, str := range list {
+		if p.is(str) {
+			return true
+		}
+	}
+	return false
+}
+
+func typeFromName(name string, args []int) SQLer {
+	switch len(args) {
+	case 0:
+		switch name {
+		case "INT", "INTEGER", "INT4":
+			return TypeInt()
+		case "SMALLINT", "INT2":
+			return TypeSmallInt()
+		case "BIGINT", "INT8":
+			return TypeBigInt()
+		case "TEXT":
+			return TypeText()
+		case "BOOL", "BOOLEAN":
+			return TypeBool()
+		case "REAL", "FLOAT4":
+			return TypeReal()
+		case "DOUBLE", "DOUBLE PRECISION", "FLOAT8":
+			return TypeDouble()
+		case "DATE":
+			return TypeDate()
+		case "TIME":
+			return TypeTime()
+		case "TIMESTAMP":
+			return TypeTimestamp()
+		case "BLOB", "BYTEA":
+			return TypeBlob()
+		case "JSON":
+			return TypeJSON()
+		case "UUID":
+			return TypeUUID()
+		case "INTERVAL":
+			return TypeInterval()
+		}
+	case 1:
+		switch name {
+		case "VARCHAR", "CHARACTER VARYING":
+			return TypeVarchar(args[0])
+		case "CHAR", "CHARACTER":
+			return TypeChar(args[0])
+		}
+	case 2:
+		switch name {
+		case "DECIMAL", "NUMERIC":
+			return TypeDecimal(args[0], args[1])
+		}
+	}
+	if len(args) > 0 {
+		list := make([]string, len(args))
+		for i := range args {
+			list[i] = strconv.Itoa(args[i])
+		}
+		name = fmt.Sprintf("%s(%s)", name, strings.Join(list, ", "))
+	}
+	return TypeRaw(name)
+}
+
+func (p *parser) parseTableConstraint() (CreateTableOption, error) {
+	var (
+		name string
+		cst  SQLer
+		err  error
+	)
+	if p.accept("CONSTRAINT") {
+		if name, err = p.parseName(); err != nil {
+			return nil, err
+		}
+	}
+	switch {
+	case p.accept("PRIMARY"):
+		if err := p.expect("KEY"); err != nil {
+			return nil, err
+		}
+		columns, err := p.parseNames()
+		if err != nil {
+			return nil, err
+		}
+		cst = PrimaryKey(columns...)
+	case p.accept("UNIQUE"):
+		columns, err := p.parseNames()
+		if err != nil {
+			return nil, err
+		}
+		cst = Unique(columns...)
+	case p.accept("CHECK"):
+		cdt, err := p.parseCheck()
+		if err != nil {
+			return nil, err
+		}
+		cst = Check(cdt)
+	case p.accept("FOREIGN"):
+		if err := p.expect("KEY"); err != nil {
+			return nil, err
+		}
+		columns, err := p.parseNames()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expect("REFERENCES"); err != nil {
+			return nil, err
+		}
+		table, refs, opts, err := p.parseReferences()
+		if err != nil {
+			return nil, err
+		}
+		cst = ForeignKey(columns, table, refs, opts...)
+	default:
+		return nil, p.unexpected()
+	}
+	if name != "" {
+		cst = Constraint(name, cst)
+	}
+	return CreateConstraint(cst), nil
+}
+
+func (p *parser) parseCheck() (SQLer, error) {
+	if err := p.expect("("); err != nil {
+		return nil, err
+	}
+	cdt, err := p.parsePredicate()
+	if err != nil {
+		return nil, err
+	}
+	return cdt, p.expect(")")
+}
+
+func (p *parser) parseReferences() (string, []string, []ForeignKeyOption, error) {
+	table, err := p.parseName()
+	if err != nil {
+		return "", nil, nil, err
+	}
+	var (
+		columns []string
+		options []ForeignKeyOption
+	)
+	if p.is("(") {
+		if columns, err = p.parseNames(); err != nil {
+			return "", nil, nil, err
+		}
+	}
+	for p.accept("ON") {
+		set := OnUpdate
+		if p.accept("DELETE") {
+			set = OnDelete
+		} else if err := p.expect("UPDATE"); err != nil {
+			return "", nil, nil, err
+		}
+		action, err := p.parseRefAction()
+		if err != nil {
+			return "", nil, nil, err
+		}
+		options = append(options, set(action))
+	}
+	return table, columns, options, nil
+}
+
+func (p *parser) parseRefAction() (string, error) {
+	switch {
+	case p.accept("CASCADE"):
+		return Cascade, nil
+	case p.accept("RESTRICT"):
+		return Restrict, nil
+	case p.accept("SET"):
+		if p.accept("NULL") {
+			return SetNull, nil
+		}
+		return SetDefault, p.expect("DEFAULT")
+	case p.accept("NO"):
+		return NoAction, p.expect("ACTION")
+	default:
+		return "", p.unexpected()
+	}
+}
+
+// parseNames parses a list of names between parenthesis. The sort order that
+// can follow a name is ignored.
+func (p *parser) parseNames() ([]string, error) {
+	if err := p.expect("("); err != nil {
+		return nil, err
+	}
+	var list []string
+	for {
+		pos := p.peek().pos
+		name, err := p.parseName()
+		if err != nil {
+			return nil, err
+		}
+		if !isValidIdentifier(name) {
+			return nil, p.errorAt(pos, "invalid identifier %q", name)
+		}
+		list = append(list, name)
+		if !p.accept("ASC") {
+			p.accept("DESC")
+		}
+		if !p.accept(",") {
+			break
+		}
+	}
+	return list, p.expect(")")
+}
+
+func (p *parser) parseIfNotExists() (bool, error) {
+	if !p.accept("IF") {
+		return false, nil
+	}
+	if err := p.expect("NOT"); err != nil {
+		return false, err
+	}
+	return true, p.expect("EXISTS")
+}
+
+func (p *parser) parseCreateIndex() (SQLer, error) {
+	var (
+		i   CreateIndex
+		err error
+	)
+	i.unique = p.accept("UNIQUE")
+	if err := p.expect("INDEX"); err != nil {
+		return nil, err
+	}
+	i.concurrently = p.accept("CONCURRENTLY")
+	if i.exists, err = p.parseIfNotExists(); err != nil {
+		return nil, err
+	}
+	pos := p.peek().pos
+	if i.name, err = p.parseName(); err != nil {
+		return nil, err
+	}
+	if !isValidIdentifier(i.name) {
+		return nil, p.errorAt(pos, "invalid identifier %q", i.name)
+	}
+	if err := p.expect("ON"); err != nil {
+		return nil, err
+	}
+	if i.table, err = p.parseIdent(); err != nil {
+		return nil, err
+	}
+	if err := p.expect("("); err != nil {
+		return nil, err
+	}
+	for {
+		expr, err := p.parseExpr()
+		if err != nil {
+			return nil, err
+		}
+		if id, ok := expr.(ident); ok && len(id.parents) == 0 {
+			i.columns = append(i.columns, expr)
+		} else {
+			i.columns = append(i.columns, indexExpr{inner: expr})
+		}
+		if !p.accept("ASC") {
+			p.accept("DESC")
+		}
+		if !p.accept(",") {
+			break
+		}
+	}
+	if err := p.expect(")"); err != nil {
+		return nil, err
+	}
+	if p.accept("WHERE") {
+		if i.where, err = p.parsePredicate(); err != nil {
+			return nil, err
+		}
+	}
+	return i, nil
+}
+
+func (p *parser) parseCreateView() (SQLer, error) {
+	var (
+		v   CreateView
+		err error
+	)
+	if p.accept("OR") {
+		if err := p.expect("REPLACE"); err != nil {
+			return nil, err
+		}
+		v.replace = true
+	}
+	v.materialized = p.accept("MATERIALIZED")
+	if err := p.expect("VIEW"); err != nil {
+		return nil, err
+	}
+	if v.exists, err = p.parseIfNotExists(); err != nil {
+		return nil, err
+	}
+	if v.name, err = p.parseIdent(); err != nil {
+		return nil, err
+	}
+	if p.is("(") {
+		if v.columns, err = p.parseNames(); err != nil {
+			return nil, err
+		}
+	}
+	if err := p.expect("AS"); err != nil {
+		return nil, err
+	}
+	pos := p.peek().pos
+	q, err := p.parseSelect()
+	if err != nil {
+		return nil, err
+	}
+	if n := q.columnsCount(); n > 0 && len(v.columns) > 0 && n != len(v.columns) {
+		return nil, p.errorAt(pos, "select returns %d columns, %d expected", n, len(v.columns))
+	}
+	v.query = q
+	return v, nil
+}
+
+func (p *parser) parsePredicate() (SQLer, error) {
+	pos := p.peek().pos
+	expr, err := p.parseExpr()
+	if err != nil {
+		return nil, err
+	}
+	if !acceptRelational(expr) {
+		return nil, p.errorAt(pos, "predicate expected")
+	}
+	return expr, nil
+}
+
+func (p *parser) parseExpr() (SQLer, error) {
+	return p.parseOr()
+}
+
+func (p *parser) parseOr() (SQLer, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.accept("OR") {
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = Or(left, right)
+	}
+	return left, nil
+}
+
+func (p *parser) parseAnd() (SQLer, error) {
+	left, err := p.parseNot()
+	if err != nil {
+		return nil, err
+	}
+	for p.accept("AND") {
+		right, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		left = And(left, right)
+	}
+	return left, nil
+}
+
+func (p *parser) parseNot() (SQLer, error) {
+	if p.accept("NOT") {
+		expr, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		return Not(expr), nil
+	}
+	return p.parseCompare()
+}
+
+func (p *parser) parseCompare() (SQLer, error) {
+	left, err := p.parseConcat()
+	if err != nil {
+		return nil, err
+	}
+	if p.accept("IS") {
+		isnot := p.accept("NOT")
+		if err := p.expect("NULL"); err != nil {
+			return nil, err
+		}
+		if isnot {
+			return IsNotNullTest(left), nil
+		}
+		return IsNullTest(left), nil
+	}
+	negate := p.accept("NOT")
+	switch {
+	case p.accept("LIKE"):
+		right, err := p.parseConcat()
+		if err != nil {
+			return nil, err
+		}
+		if negate {
+			return NotLike(left, right), nil
+		}
+		return Like(left, right), nil
+	case p.accept("IN"):
+		right, err := p.parseInList()
+		if err != nil {
+			return nil, err
+		}
+		if negate {
+			return NotIn(left, right), nil
+		}
+		return In(left, right), nil
+	case p.accept("BETWEEN"):
+		lower, err := p.parseConcat()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expect("AND"); err != nil {
+			return nil, err
+		}
+		upper, err := p.parseConcat()
+		if err != nil {
+			return nil, err
+		}
+		expr := Between(left, lower, upper)
+		if negate {
+			expr = Not(expr)
+		}
+		return expr, nil
+	case negate:
+		return nil, p.unexpected()
+	}
+	ops := map[string]func(SQLer, SQLer) SQLer{
+		"=":  Equal,
+		"<>": NotEqual,
+		"!=": NotEqual,
+		"<":  LesserThan,
+		"<=": LesserOrEqual,
+		">":  GreaterThan,
+		">=": GreaterOrEqual,
+	}
+	tok := p.peek()
+	if fn, ok := ops[tok.literal]; ok && tok.kind == punct {
+		p.next()
+		var quantify func(SQLer) SQLer
+		switch next := p.peekAt(1); {
+		case next.literal != "(" || next.kind != punct:
+		case p.accept("ANY"), p.accept("SOME"):
+			quantify = Any
+		case p.accept("ALL"):
+			quantify = All
+		}
+		if quantify == nil {
+			right, err := p.parseConcat()
+			if err != nil {
+				return nil, err
+			}
+			return fn(left, right), nil
+		}
+		if err := p.expect("("); err != nil {
+			return nil, err
+		}
+		q, err := p.parseQuery()
+		if err != nil {
+			return nil, err
+		}
+		return fn(left, quantify(q)), p.expect(")")
+	}
+	return left, nil
+}
+
+func (p *parser) parseInList() (SQLer, error) {
+	if next := p.peekAt(1); next.kind == word && (strings.EqualFold(next.literal, "SELECT") || strings.EqualFold(next.literal, "WITH")) {
+		if err := p.expect("("); err != nil {
+			return nil, err
+		}
+		q, err := p.parseQuery()
+		if err != nil {
+			return nil, err
+		}
+		return q, p.expect(")")
+	}
+	list, err := p.parseParenList(p.parseExpr)
+	if err != nil {
+		return nil, err
+	}
+	return NewList(list...), nil
+}
+
+// parseConcat parses the concatenation of strings with ||. It binds less
+// tightly than the arithmetic operators.
+func (p *parser) parseConcat() (SQLer, error) {
+	left, err := p.parseAdditive()
+	if err != nil {
+		return nil, err
+	}
+	for p.accept("||") {
+		right, err := p.parseAdditive()
+		if err != nil {
+			return nil, err
+		}
+		left = Concat(left, right)
+	}
+	return left, nil
+}
+
+func (p *parser) parseAdditive() (SQLer, error) {
+	left, err := p.parseMultiplicative()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		var fn func(SQLer, SQLer) SQLer
+		switch {
+		case p.accept("+"):
+			fn = Add
+		case p.accept("-"):
+			fn = Subtract
+		default:
+			return left, nil
+		}
+		right, err := p.parseMultiplicative()
+		if err != nil {
+			return nil, err
+		}
+		left = fn(left, right)
+	}
+}
+
+func (p *parser) parseMultiplicative() (SQLer, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		var fn func(SQLer, SQLer) SQLer
+		switch {
+		case p.accept("*"):
+			fn = Multiply
+		case p.accept("/"):
+			fn = Divide
+		case p.accept("%"):
+			fn = Modulo
+		default:
+			return left, nil
+		}
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = fn(left, right)
+	}
+}
+
+func (p *parser) parseUnary() (SQLer, error) {
+	if p.is("-") && p.peekAt(1).kind == number {
+		p.next()
+		return p.parseNumber("-")
+	}
+	if p.accept("-") {
+		expr, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return Negate(expr), nil
+	}
+	return p.parsePrimary()
+}
+
+func (p *parser) parsePrimary() (SQLer, error) {
+	tok := p.peek()
+	switch tok.kind {
+	case number:
+		return p.parseNumber("")
+	case text:
+		p.next()
+		return NewLiteral(tok.literal), nil
+	case param:
+		p.next()
+		return placeholder{name: strings.TrimLeft(tok.literal, "?$:@")}, nil
+	case quoted:
+		return p.parseIdentOrCall()
+	case word:
+	default:
+		if p.accept("(") {
+			var (
+				expr SQLer
+				err  error
+			)
+			if p.is("SELECT") || p.is("WITH") {
+				expr, err = p.parseQuery()
+			} else {
+				expr, err = p.parseExpr()
+			}
+			if err != nil {
+				return nil, err
+			}
+			return expr, p.expect(")")
+		}
+		return nil, p.unexpected()
+	}
+	switch {
+	case p.accept("NULL"):
+		return NewLiteral(nil), nil
+	case p.accept("TRUE"):
+		return NewLiteral(true), nil
+	case p.accept("FALSE"):
+		return NewLiteral(false), nil
+	case p.accept("CASE"):
+		return p.parseCase()
+	case p.accept("EXISTS"):
+		if err := p.expect("("); err != nil {
+			return nil, err
+		}
+		q, err := p.parseQuery()
+		if err != nil {
+			return nil, err
+		}
+		return Exists(q), p.expect(")")
+	case p.accept("INTERVAL"):
+		return p.parseInterval()
+	case p.is("CAST") && p.peekAt(1).literal == "(":
+		return p.parseCast()
+	case isReserved(tok.literal):
+		return nil, p.unexpected()
+	default:
+		return p.parseIdentOrCall()
+	}
+}
+
+func (p *parser) parseNumber(sign string) (SQLer, error) {
+	tok := p.next()
+	str := sign + tok.literal
+	if n, err := strconv.ParseInt(str, 10, 64); err == nil {
+		return NewLiteral(n), nil
+	}
+	f, err := strconv.ParseFloat(str, 64)
+	if err != nil {
+		return nil, p.errorAt(tok.pos, "invalid number %s", tok.literal)
+	}
+	return NewLiteral(f), nil
+}
+
+func (p *parser) parseInterval() (SQLer, error) {
+	var (
+		tok   = p.next()
+		value string
+		unit  string
+	)
+	switch tok.kind {
+	case number:
+		value = tok.literal
+	case text:
+		value, unit, _ = strings.Cut(strings.TrimSpace(tok.literal), " ")
+	default:
+		return nil, p.errorAt(tok.pos, "unexpected %s in interval", tok)
+	}
+	if unit == "" {
+		next := p.next()
+		if next.kind != word {
+			return nil, p.errorAt(next.pos, "unexpected %s in interval", next)
+		}
+		unit = next.literal
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		return nil, p.errorAt(tok.pos, "invalid interval value %s", value)
+	}
+	unit = strings.TrimSuffix(strings.ToUpper(strings.TrimSpace(unit)), "S")
+	for u, str := range durations {
+		if str == unit {
+			return newDuration(n, u), nil
+		}
+	}
+	return nil, p.errorAt(tok.pos, "unknown interval unit %s", unit)
+}
+
+func (p *parser) parseCast() (SQLer, error) {
+	p.next()
+	p.next()
+	expr, err := p.parseExpr()
+	if err != nil {
+		return nil, err
+	}
+	if err := p.expect("AS"); err != nil {
+		return nil, err
+	}
+	kind, err := p.parseType()
+	if err != nil {
+		return nil, err
+	}
+	return Cast(expr, kind), p.expect(")")
+}
+
+func (p *parser) parseCase() (SQLer, error) {
+	var (
+		k   kase
+		err error
+	)
+	if !p.is("WHEN") {
+		if k.expr, err = p.parseExpr(); err != nil {
+			return nil, err
+		}
+	}
+	for p.accept("WHEN") {
+		test, err := p.parseExpr()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expect("THEN"); err != nil {
+			return nil, err
+		}
+		csq, err := p.parseExpr()
+		if err != nil {
+			return nil, err
+		}
+		k.test = append(k.test, test)
+		k.csq = append(k.csq, csq)
+	}
+	if len(k.test) == 0 {
+		return nil, p.unexpected()
+	}
+	if p.accept("ELSE") {
+		if k.alt, err = p.parseExpr(); err != nil {
+			return nil, err
+		}
+	}
+	return k, p.expect("END")
+}
+
+func (p *parser) parseIdentOrCall() (SQLer, error) {
+	if p.peek().kind == word && p.peekAt(1).literal == "(" && p.peekAt(1).kind == punct {
+		name := p.next().literal
+		p.next()
+		var args []SQLer
+		if !p.accept(")") {
+			for {
+				if p.accept("*") {
+					args = append(args, NewIdent("*"))
+				} else {
+					distinct := len(args) == 0 && p.accept("DISTINCT")
+					arg, err := p.parseExpr()
+					if err != nil {
+						return nil, err
+					}
+					if distinct {
+						arg = Distinct(arg)
+					}
+					args = append(args, arg)
+				}
+				if !p.accept(",") {
+					break
+				}
+			}
+			if err := p.expect(")"); err != nil {
+				return nil, err
+			}
+		}
+		fn := Func(name, args...)
+		if p.accept("OVER") {
+			return p.parseOver(fn)
+		}
+		return fn, nil
+	}
+	return p.parseIdent()
+}
+
+// parseOver parses the window of the function fn: the name of a window
+// defined by the WINDOW clause or a specification between parenthesis.
+func (p *parser) parseOver(fn SQLer) (SQLer, error) {
+	w := window{
+		fn: fn,
+	}
+	if p.is("(") {
+		return w, p.parseWindowSpec(&w)
+	}
+	pos := p.peek().pos
+	name, err := p.parseName()
+	if err != nil {
+		return nil, err
+	}
+	if !isValidIdentifier(name) {
+		return nil, p.errorAt(pos, "invalid identifier %q", name)
+	}
+	w.ref = name
+	return w, nil
+}
+
+var windowKeywords = []string{
+	"GROUPS",
+	"ORDER",
+	"PARTITION",
+	"RANGE",
+	"ROWS",
+}
+
+func (p *parser) parseWindowSpec(w *window) error {
+	if err := p.expect("("); err != nil {
+		return err
+	}
+	if tok := p.peek(); tok.kind == quoted || (tok.kind == word && !p.isOneOf(windowKeywords)) {
+		pos := tok.pos
+		name, err := p.parseName()
+		if err != nil {
+			return err
+		}
+		if !isValidIdentifier(name) {
+			return p.errorAt(pos, "invalid identifier %q", name)
+		}
+		w.ref = name
+	}
+	var err error
+	if p.accept("PARTITION") {
+		if err := p.expect("BY"); err != nil {
+			return err
+		}
+		if w.partition, err = p.parseList(p.parseExpr); err != nil {
+			return err
+		}
+	}
+	if p.accept("ORDER") {
+		if err := p.expect("BY"); err != nil {
+			return err
+		}
+		if w.orderby, err = p.parseList(p.parseOrder); err != nil {
+			return err
+		}
+	}
+	for _, mode := range []string{"ROWS", "RANGE", "GROUPS"} {
+		pos := p.peek().pos
+		if !p.accept(mode) {
+			continue
+		}
+		var bounds []SQLer
+		if p.accept("BETWEEN") {
+			lower, err := p.parseBound()
+			if err != nil {
+				return err
+			}
+			if err := p.expect("AND"); err != nil {
+				return err
+			}
+			upper, err := p.parseBound()
+			if err != nil {
+				return err
+			}
+			bounds = append(bounds, lower, upper)
+		} else {
+			b, err := p.parseBound()
+			if err != nil {
+				return err
+			}
+			bounds = append(bounds, b)
+		}
+		if err := withFrame(mode, bounds)(w); err != nil {
+			return p.errorAt(pos, "%s", err)
+		}
+		break
+	}
+	return p.expect(")")
+}
+
+func (p *parser) parseBound() (SQLer, error) {
+	switch {
+	case p.accept("UNBOUNDED"):
+		if p.accept("PRECEDING") {
+			return UnboundedPreceding(), nil
+		}
+		return UnboundedFollowing(), p.expect("FOLLOWING")
+	case p.accept("CURRENT"):
+		return CurrentRow(), p.expect("ROW")
+	}
+	n, err := p.parseCount()
+	if err != nil {
+		return nil, err
+	}
+	if p.accept("PRECEDING") {
+		return Preceding(n), nil
+	}
+	return Following(n), p.expect("FOLLOWING")
+}
+
+func (p *parser) parseIdent() (SQLer, error) {
+	var parts []string
+	for {
+		if len(parts) > 0 && p.accept("*") {
+			parts = append(parts, "*")
+			break
+		}
+		pos := p.peek().pos
+		name, err := p.parseName()
+		if err != nil {
+			return nil, err
+		}
+		if !isValidIdentifier(name) {
+			return nil, p.errorAt(pos, "invalid identifier %q", name)
+		}
+		parts = append(parts, name)
+		if !p.accept(".") {
+			break
+		}
+	}
+	n := len(parts) - 1
+	return NewIdent(parts[n], parts[:n]...), nil
+}
+
+func (p *parser) parseName() (string, error) {
+	tok := p.peek()
+	switch tok.kind {
+	case word:
+		if isReserved(tok.literal) {
+			return "", p.unexpected()
+		}
+		p.next()
+		return tok.literal, nil
+	case quoted:
+		p.next()
+		str := tok.literal[1 : len(tok.literal)-1]
+		if isValidLiteralString(str) && !strings.Contains(str, ".") {
+			return str, nil
+		}
+		return tok.literal, nil
+	default:
+		return "", p.unexpected()
+	}
+}
+
+func (p *parser) parseList(parse func() (SQLer, error)) ([]SQLer, error) {
+	var list []SQLer
+	for {
+		s, err := parse()
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, s)
+		if !p.accept(",") {
+			break
+		}
+	}
+	return list, nil
+}
+
+func (p *parser) parseParenList(parse func() (SQLer, error)) ([]SQLer, error) {
+	if err := p.expect("("); err != nil {
+		return nil, err
+	}
+	list, err := p.parseList(parse)
+	if err != nil {
+		return nil, err
+	}
+	return list, p.expect(")")
+}
+
+func (p *parser) peek() token {
+	return p.peekAt(0)
+}
+
+func (p *parser) peekAt(n int) token {
+	if p.pos+n >= len(p.tokens) {
+		return p.tokens[len(p.tokens)-1]
+	}
+	return p.tokens[p.pos+n]
+}
+
+func (p *parser) next() token {
+	tok := p.peek()
+	if p.pos < len(p.tokens)-1 {
+		p.pos++
+	}
+	return tok
+}
+
+func (p *parser) done() bool {
+	return p.peek().kind == eof
+}
+
+// is reports whether the current token is the keyword or the punctuation
+// given by str.
+func (p *parser) is(str string) bool {
+	tok := p.peek()
+	switch tok.kind {
+	case word:
+		return strings.EqualFold(tok.literal, str)
+	case punct:
+		return tok.literal == str
+	default:
+		return false
+	}
+}
+
+func (p *parser) accept(str string) bool {
+	if p.is(str) {
+		p.next()
+		return true
+	}
+	return false
+}
+
+func (p *parser) expect(str string) error {
+	if !p.accept(str) {
+		tok := p.peek()
+		return p.errorAt(tok.pos, "expected %s, got %s", str, tok)
+	}
+	return nil
+}
+
+func (p *parser) unexpected() error {
+	tok := p.peek()
+	return p.errorAt(tok.pos, "unexpected %s", tok)
+}
+
+func (p *parser) errorAt(pos position, format string, args ...interface{}) error {
+	return ParseError{
+		Line:    pos.line,
+		Column:  pos.column,
+		Message: fmt.Sprintf(format, args...),
+	}
+}
+
+var reserved = map[string]struct{}{
+	"ALL":       {},
+	"AND":       {},
+	"AS":        {},
+	"ASC":       {},
+	"BETWEEN":   {},
+	"BY":        {},
+	"CASE":      {},
+	"DELETE":    {},
+	"DESC":      {},
+	"DISTINCT":  {},
+	"ELSE":      {},
+	"END":       {},
+	"EXISTS":    {},
+	"FETCH":     {},
+	"FROM":      {},
+	"FULL":      {},
+	"GROUP":     {},
+	"HAVING":    {},
+	"IN":        {},
+	"INNER":     {},
+	"INSERT":    {},
+	"INTO":      {},
+	"IS":        {},
+	"JOIN":      {},
+	"LEFT":      {},
+	"LIKE":      {},
+	"LIMIT":     {},
+	"NOT":       {},
+	"NULL":      {},
+	"OFFSET":    {},
+	"ON":        {},
+	"OR":        {},
+	"ORDER":     {},
+	"OUTER":     {},
+	"RETURNING": {},
+	"RIGHT":     {},
+	"SELECT":    {},
+	"SET":       {},
+	"THEN":      {},
+	"UNION":     {},
+	"UPDATE":    {},
+	"USING":     {},
+	"VALUES":    {},
+	"WHEN":      {},
+	"WHERE":     {},
+	"WINDOW":    {},
+	"WITH":      {},
+}
+
+func isReserved(str string) bool {
+	_, ok := reserved[strings.ToUpper(str)]
+	return ok
+}
@@ -0,0 +1,231 @@
+package quel
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	t.Run("roundtrip", testParseRoundTrip)
+	t.Run("normalize", testParseNormalize)
+	t.Run("dialect", testParseDialect)
+	t.Run("error", testParseError)
+	t.Run("script", testParseScript)
+}
+
+func testParseRoundTrip(t *testing.T) {
+	queries := []string{
+		"SELECT * FROM users",
+		"SELECT id, first, last FROM users LIMIT 10",
+		"SELECT u.id, u.first, u.last FROM users AS u WHERE role = ? ORDER BY first ASC, last ASC",
+		"SELECT COUNT(id) FROM users GROUP BY active",
+		"SELECT DISTINCT role FROM users LIMIT 10 OFFSET 20",
+		"SELECT u.id, u.first, u.last, p.id, p.name FROM users AS u INNER JOIN positions AS p ON u.id = p.user",
+		"SELECT u.id, p.name FROM users AS u LEFT OUTER JOIN positions AS p USING (id) WHERE p.name IS NOT NULL",
+		"SELECT role, COUNT(*) AS total FROM users GROUP BY role HAVING COUNT(*) > 10 ORDER BY role DESC",
+		"SELECT role FROM users GROUP BY role ORDER BY COUNT(*) DESC",
+		"SELECT COUNT(DISTINCT role) FROM users",
+		"SELECT -a, -(a + b), 2 - -a FROM t",
+		"SELECT id FROM users WHERE age = ALL (SELECT age FROM admins) AND age > ANY (SELECT age FROM guests)",
+		"SELECT NOW(), 1 + 1",
+		"SELECT id, first FROM users ORDER BY 2 ASC, id DESC",
+		"SELECT id FROM users WHERE (role = 'admin' OR role = 'it''s') AND active = true",
+		"SELECT id FROM users WHERE role IN ('admin', 'test') AND id NOT IN (SELECT user FROM banned)",
+		"SELECT id FROM users WHERE age BETWEEN 18 AND 65 AND NOT name LIKE 'r%'",
+		"SELECT a FROM t WHERE a = 1 AND NOT (b = 2 OR c = 3)",
+		"SELECT a FROM t WHERE NOT (a = 1 AND b = 2)",
+		"SELECT id FROM users WHERE EXISTS (SELECT id FROM positions WHERE user = users.id)",
+		"SELECT id, (salary * 12) + bonus AS total FROM users WHERE created >= NOW() - INTERVAL 3 DAY",
+		"SELECT CASE role WHEN 'admin' THEN 1 ELSE 0 END AS admin FROM users",
+		"WITH actives(id) AS (SELECT id FROM users WHERE active = $1) SELECT id FROM actives",
+		"SELECT id FROM users UNION ALL SELECT id FROM admins",
+		"SELECT t.id FROM (SELECT id FROM users UNION SELECT id FROM admins) AS t",
+		"SELECT u.id, p.name FROM users AS u, positions AS p WHERE u.id = p.user",
+		"SELECT first || ' ' || last AS name FROM users WHERE email LIKE first || '%'",
+		"SELECT CAST(age AS TEXT) FROM users WHERE CAST(created AS DATE) = '2024-01-01'",
+		"SELECT id, ROW_NUMBER() OVER (PARTITION BY team ORDER BY created DESC) AS rank FROM users",
+		"SELECT id, SUM(amount) OVER (ORDER BY created ASC ROWS BETWEEN UNBOUNDED PRECEDING AND CURRENT ROW) FROM payments",
+		"SELECT id, RANK() OVER w FROM users WINDOW w AS (PARTITION BY team ORDER BY score DESC)",
+		"INSERT INTO users(first, last) VALUES ('roger', 'lamotte'), (?, ?)",
+		"INSERT INTO users VALUES (default, 'roger', 'lamotte') RETURNING id",
+		"UPDATE users SET role = 'test', active = 1 WHERE active = ?",
+		"UPDATE users AS u SET u.role = ? WHERE u.id = ? RETURNING u.id",
+		"DELETE FROM users WHERE conn >= (SELECT AVG(conn) FROM users GROUP BY id) AND role <> ?",
+		"DELETE FROM users AS u WHERE u.role <> ?",
+		"WITH RECURSIVE tree(id, parent) AS (SELECT id, parent FROM categories WHERE parent IS NULL UNION ALL SELECT c.id, c.parent FROM categories AS c INNER JOIN tree AS t ON c.parent = t.id) SELECT id FROM tree",
+		"WITH actives AS MATERIALIZED (SELECT id FROM users WHERE active = true) SELECT id FROM actives",
+		"WITH olds(id) AS (DELETE FROM users WHERE active = false RETURNING id) INSERT INTO archives(id) SELECT id FROM olds",
+		"INSERT INTO archives(id, name) SELECT id, name FROM users UNION SELECT id, name FROM admins",
+		"WITH admins(id) AS (SELECT user FROM roles WHERE name = 'admin') UPDATE users SET active = true WHERE id IN (SELECT id FROM admins)",
+		"CREATE TABLE IF NOT EXISTS users (id INTEGER NOT NULL PRIMARY KEY, email VARCHAR(255) UNIQUE, role TEXT DEFAULT 'user', team INTEGER REFERENCES teams(id) ON DELETE CASCADE, CONSTRAINT users_id CHECK (id > 0))",
+		"CREATE TABLE members (team INTEGER, user INTEGER, PRIMARY KEY (team, user), FOREIGN KEY (user) REFERENCES users(id) ON UPDATE SET NULL)",
+		"CREATE TABLE archives AS SELECT id FROM users",
+		"CREATE UNIQUE INDEX users_idx ON users (email, (LOWER(name))) WHERE active = true",
+		"CREATE OR REPLACE VIEW actives(id) AS SELECT id FROM users WHERE active = true",
+	}
+	for _, q := range queries {
+		s, err := Parse(q, Default)
+		if err != nil {
+			t.Errorf("%s: error parsing query! %s", q, err)
+			continue
+		}
+		str, _, err := s.SQL()
+		if err != nil {
+			t.Errorf("%s: error building query! %s", q, err)
+			continue
+		}
+		if str != q {
+			t.Errorf("queries mismatched!")
+			t.Logf("\twant: %s", q)
+			t.Logf("\tgot:  %s", str)
+		}
+	}
+}
+
+func testParseNormalize(t *testing.T) {
+	data := []struct {
+		Input string
+		Want  string
+	}{
+		{
+			Input: "select id from users u join positions p on u.id = p.user where u.id != 1;",
+			Want:  "SELECT id FROM users AS u INNER JOIN positions AS p ON u.id = p.user WHERE u.id <> 1",
+		},
+		{
+			Input: "SELECT id\n-- active users only\nFROM users WHERE active IS NULL ORDER BY id",
+			Want:  "SELECT id FROM users WHERE active IS NULL ORDER BY id ASC",
+		},
+		{
+			Input: "SELECT id FROM users WHERE age < some (select age FROM admins)",
+			Want:  "SELECT id FROM users WHERE age < ANY (SELECT age FROM admins)",
+		},
+		{
+			Input: "SELECT id /* primary key */ FROM users",
+			Want:  "SELECT id FROM users",
+		},
+		{
+			Input: `SELECT "id", "user"."name" FROM "user" OFFSET 10 ROWS FETCH FIRST 5 ROWS ONLY`,
+			Want:  "SELECT id, user.name FROM user LIMIT 5 OFFSET 10",
+		},
+		{
+			Input: "SELECT id FROM users WHERE name = :name OR email = @email",
+			Want:  "SELECT id FROM users WHERE name = ? OR email = ?",
+		},
+		{
+			Input: "create table t (id integer primary key autoincrement, price numeric(10, 0), x double precision, y datetime, z unsigned big int) without rowid",
+			Want:  "CREATE TABLE t (id INTEGER PRIMARY KEY GENERATED BY DEFAULT AS IDENTITY, price DECIMAL(10, 0), x DOUBLE PRECISION, y DATETIME, z UNSIGNED BIG INT)",
+		},
+		{
+			Input: "CREATE TABLE t (id bigint generated always as identity, name varchar(64) collate nocase not null)",
+			Want:  "CREATE TABLE t (id BIGINT GENERATED BY DEFAULT AS IDENTITY, name VARCHAR(64) NOT NULL)",
+		},
+	}
+	for _, d := range data {
+		s, err := Parse(d.Input, Default)
+		if err != nil {
+			t.Errorf("%s: error parsing query! %s", d.Input, err)
+			continue
+		}
+		str, _, err := s.SQL()
+		if err != nil {
+			t.Errorf("%s: error building query! %s", d.Input, err)
+			continue
+		}
+		if str != d.Want {
+			t.Errorf("queries mismatched!")
+			t.Logf("\twant: %s", d.Want)
+			t.Logf("\tgot:  %s", str)
+		}
+	}
+}
+
+func testParseDialect(t *testing.T) {
+	s, err := Parse("SELECT `id` FROM `users` WHERE `name` = \"roger\" AND created > NOW() - INTERVAL 1 DAY", MySQL)
+	if err != nil {
+		t.Fatalf("error parsing query! %s", err)
+	}
+	want := `SELECT "id" FROM "users" WHERE "name" = 'roger' AND "created" > NOW() - INTERVAL '1 day'`
+	compareDialect(t, s, Postgres, want, nil)
+
+	q, _ := NewSelect("users", SelectWhere(Equal(NewIdent("name"), Arg("name", "roger"))))
+	s, err = Parse("SELECT * FROM users WHERE name = :name", SQLite)
+	if err != nil {
+		t.Fatalf("error parsing query! %s", err)
+	}
+	want, _, _ = SQLDialect(q, SQLite)
+	compareDialect(t, s, SQLite, want, []interface{}{nil})
+
+	s, err = Parse("SELECT id FROM users WHERE id = ? OR name = :name", Default)
+	if err != nil {
+		t.Fatalf("error parsing query! %s", err)
+	}
+	compareDialect(t, s, Postgres, `SELECT "id" FROM "users" WHERE "id" = $1 OR "name" = $2`, []interface{}{nil, nil})
+
+	s, err = Parse("SELECT CAST(id AS INTEGER) || ':' || name FROM users", Default)
+	if err != nil {
+		t.Fatalf("error parsing query! %s", err)
+	}
+	compareDialect(t, s, MySQL, "SELECT CONCAT(CONCAT(CAST(`id` AS SIGNED), ':'), `name`) FROM `users`", nil)
+}
+
+func testParseError(t *testing.T) {
+	data := []struct {
+		Input  string
+		Line   int
+		Column int
+	}{
+		{Input: "SELECT FROM users", Line: 1, Column: 8},
+		{Input: "SELECT id FROM users WHERE", Line: 1, Column: 27},
+		{Input: "SELECT id\nFROM users\nWHERE name = 'roger", Line: 3, Column: 14},
+		{Input: "DELETE users", Line: 1, Column: 8},
+		{Input: "INSERT INTO users(id, name) VALUES (1)", Line: 1, Column: 36},
+		{Input: "SELECT id FROM users WHERE id + 1", Line: 1, Column: 28},
+		{Input: "SELECT id FROM users LIMIT 10 garbage", Line: 1, Column: 31},
+		{Input: "CREATE TABLE users (id INTEGER, id TEXT)", Line: 1, Column: 33},
+		{Input: "CREATE TABLE users (id, name TEXT)", Line: 1, Column: 23},
+		{Input: "CREATE TABLE users (PRIMARY KEY (id))", Line: 1, Column: 14},
+		{Input: "SELECT id FROM (SELECT id FROM users)", Line: 1, Column: 16},
+		{Input: "SELECT a FROM t /* unterminated", Line: 1, Column: 17},
+		{Input: "SELECT id, RANK() OVER (ROWS 1 FOLLOWING garbage) FROM users", Line: 1, Column: 42},
+	}
+	for _, d := range data {
+		_, err := Parse(d.Input, Default)
+		if !errors.Is(err, ErrSyntax) {
+			t.Errorf("%s: expected syntax error, got %v", d.Input, err)
+			continue
+		}
+		var pe ParseError
+		if !errors.As(err, &pe) {
+			t.Errorf("%s: expected ParseError, got %T", d.Input, err)
+			continue
+		}
+		if pe.Line != d.Line || pe.Column != d.Column {
+			t.Errorf("%s: position mismatched! want %d:%d, got %d:%d (%s)", d.Input, d.Line, d.Column, pe.Line, pe.Column, pe.Message)
+		}
+	}
+}
+
+func testParseScript(t *testing.T) {
+	script := `
+CREATE TABLE users (id INTEGER PRIMARY KEY, name TEXT NOT NULL);
+-- indexes
+CREATE INDEX users_name ON users (name);;
+SELECT id FROM users
+`
+	list, err := ParseScript(script, SQLite)
+	if err != nil {
+		t.Fatalf("error parsing script! %s", err)
+	}
+	if len(list) != 3 {
+		t.Fatalf("statements count mismatched! want 3, got %d", len(list))
+	}
+	if _, ok := list[0].(CreateTable); !ok {
+		t.Errorf("expected CreateTable, got %T", list[0])
+	}
+	if _, ok := list[1].(CreateIndex); !ok {
+		t.Errorf("expected CreateIndex, got %T", list[1])
+	}
+	if _, err := ParseScript("SELECT id FROM users SELECT id FROM groups", Default); err == nil {
+		t.Errorf("expected error when statements are not separated")
+	}
+}
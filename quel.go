@@ -16,9 +16,12 @@ func init() {
 }
 
 var (
+	ErrArg    = errors.New("invalid argument")
+	ErrColumn = errors.New("invalid column")
 	ErrIdent  = errors.New("invalid identifier")
 	ErrLimit  = errors.New("negative limit")
 	ErrSyntax = errors.New("invalid syntax")
+	ErrType   = errors.New("invalid type")
 )
 
 const null = "null"
@@ -48,6 +51,10 @@ func (i ident) Alias(name string) SQLer {
 }
 
 func (i ident) SQL() (string, []interface{}, error) {
+	return SQLDialect(i, Default)
+}
+
+func (i ident) render(r *renderer) (string, []interface{}, error) {
 	for j := range i.parents {
 		if !isValidIdentifier(i.parents[j]) {
 			return "", nil, fmt.Errorf("ident: %w %q", ErrIdent, i.parents[j])
@@ -56,8 +63,11 @@ func (i ident) SQL() (string, []interface{}, error) {
 	if !isValidIdentifier(i.name) {
 		return "", nil, fmt.Errorf("ident: %w %q", ErrIdent, i.name)
 	}
-	lines := append([]string{}, i.parents...)
-	return strings.Join(append(lines, i.name), "."), nil, nil
+	lines := make([]string, 0, len(i.parents)+1)
+	for _, p := range i.parents {
+		lines = append(lines, r.ident(p))
+	}
+	return strings.Join(append(lines, r.ident(i.name)), "."), nil, nil
 }
 
 type alias struct {
@@ -76,14 +86,19 @@ func Alias(name string, sql SQLer) SQLer {
 }
 
 func (a alias) SQL() (string, []interface{}, error) {
-	sql, args, err := a.SQLer.SQL()
+	return SQLDialect(a, Default)
+}
+
+func (a alias) render(r *renderer) (string, []interface{}, error) {
+	sql, args, err := r.subquery(a.SQLer)
 	if err != nil {
 		return "", nil, err
 	}
-	if _, ok := a.SQLer.(Select); ok {
-		return fmt.Sprintf("(%s) AS %s", sql, a.name), args, nil
+	switch a.SQLer.(type) {
+	case Select, union:
+		return fmt.Sprintf("(%s) %s %s", sql, r.keyword("AS"), r.ident(a.name)), args, nil
 	}
-	return fmt.Sprintf("%s AS %s", sql, a.name), args, nil
+	return fmt.Sprintf("%s %s %s", sql, r.keyword("AS"), r.ident(a.name)), args, nil
 }
 
 type list struct {
@@ -101,6 +116,10 @@ func (i list) Alias(name string) SQLer {
 }
 
 func (i list) SQL() (string, []interface{}, error) {
+	return SQLDialect(i, Default)
+}
+
+func (i list) render(r *renderer) (string, []interface{}, error) {
 	var (
 		b    strings.Builder
 		args []interface{}
@@ -109,7 +128,7 @@ func (i list) SQL() (string, []interface{}, error) {
 		if j > 0 {
 			b.WriteString(", ")
 		}
-		sql, as, err := p.SQL()
+		sql, as, err := r.render(p)
 		if err != nil {
 			return "", nil, err
 		}
@@ -130,6 +149,10 @@ func NewLiteral(value interface{}) SQLer {
 }
 
 func (i literal) SQL() (string, []interface{}, error) {
+	return SQLDialect(i, Default)
+}
+
+func (i literal) render(r *renderer) (string, []interface{}, error) {
 	if i.value == nil {
 		return "null", nil, nil
 	}
@@ -160,7 +183,7 @@ func (i literal) SQL() (string, []interface{}, error) {
 	case float64:
 		str = strconv.FormatFloat(val, 'g', -1, 64)
 	case bool:
-		str = strconv.FormatBool(val)
+		str = r.keyword(r.Bool(val))
 	case string:
 		str = fmt.Sprintf("'%s'", strings.ReplaceAll(val, "'", "''"))
 	case time.Time:
@@ -190,15 +213,28 @@ func Arg(name string, a interface{}) SQLer {
 }
 
 func (a arg) SQL() (string, []interface{}, error) {
+	return SQLDialect(a, Default)
+}
+
+func (a arg) render(r *renderer) (string, []interface{}, error) {
 	if a.value == nil {
 		a.value = null
 	}
-	placeholder := "?"
-	if isNumeric(a.name) {
-		placeholder = fmt.Sprintf("$%s", a.name)
-	}
-	// return fmt.Sprintf("@%s", a.name), []interface{}{a.value}, nil
-	return placeholder, []interface{}{a.value}, nil
+	return r.bind(a.name, a.value)
+}
+
+// placeholder is a bind parameter found by Parse. Unlike an argument, it has
+// no value: nil is returned in its place with the query.
+type placeholder struct {
+	name string
+}
+
+func (p placeholder) SQL() (string, []interface{}, error) {
+	return SQLDialect(p, Default)
+}
+
+func (p placeholder) render(r *renderer) (string, []interface{}, error) {
+	return r.bind(p.name, nil)
 }
 
 type raw string
@@ -215,13 +251,13 @@ func (r raw) SQL() (string, []interface{}, error) {
 	return string(r), nil, nil
 }
 
-func writeSQL(b io.StringWriter, parts ...SQLer) ([]interface{}, error) {
+func writeSQL(r *renderer, b io.StringWriter, parts ...SQLer) ([]interface{}, error) {
 	var args []interface{}
 	for i, s := range parts {
 		if i > 0 {
 			b.WriteString(", ")
 		}
-		sql, as, err := s.SQL()
+		sql, as, err := r.subquery(s)
 		if err != nil {
 			return nil, err
 		}
@@ -334,6 +370,15 @@ var keywords = []string{
 	"union all",
 }
 
+func isValidName(str string) bool {
+	for i, r := range str {
+		if (i == 0 && !isLetter(r)) || !isIdent(r) {
+			return false
+		}
+	}
+	return str != ""
+}
+
 func isNumeric(str string) bool {
 	_, err := strconv.Atoi(str)
 	return err == nil
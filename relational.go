@@ -73,6 +73,8 @@ func NotLike(left, right SQLer) SQLer {
 	return newCompare(notlike, left, right)
 }
 
+// In tests whether left is one of the values of right. A list created with
+// NewList or a query is written between parentheses.
 func In(left, right SQLer) SQLer {
 	return newCompare(in, left, right)
 }
@@ -98,24 +100,28 @@ func newCompare(op uint8, left, right SQLer) SQLer {
 }
 
 func (c compare) SQL() (string, []interface{}, error) {
+	return SQLDialect(c, Default)
+}
+
+func (c compare) render(r *renderer) (string, []interface{}, error) {
 	var args []interface{}
-	left, as, err := c.left.SQL()
+	left, as, err := r.render(c.left)
 	if err != nil {
 		return "", nil, err
 	}
 	args = append(args, as...)
 
 	if c.op == isnull || c.op == isnotnull {
-		return fmt.Sprintf("%s %s", left, cmpops[c.op]), args, nil
+		return fmt.Sprintf("%s %s", left, r.keyword(cmpops[c.op])), args, nil
 	}
 
-	right, as, err := c.right.SQL()
+	right, as, err := r.subquery(c.right)
 	if err != nil {
 		return "", nil, err
 	}
 
 	switch c.right.(type) {
-	case list, Select:
+	case list, Select, union:
 		right = fmt.Sprintf("(%s)", right)
 	default:
 	}
@@ -126,7 +132,7 @@ func (c compare) SQL() (string, []interface{}, error) {
 	if !ok {
 		return "", nil, fmt.Errorf("unsupported comparison operator")
 	}
-	return fmt.Sprintf("%s %s %s", left, op, right), args, nil
+	return fmt.Sprintf("%s %s %s", left, r.keyword(op), right), args, nil
 }
 
 type between struct {
@@ -144,6 +150,10 @@ func Between(value, left, right SQLer) SQLer {
 }
 
 func (b between) SQL() (string, []interface{}, error) {
+	return SQLDialect(b, Default)
+}
+
+func (b between) render(r *renderer) (string, []interface{}, error) {
 	var (
 		sql   string
 		left  string
@@ -152,22 +162,22 @@ func (b between) SQL() (string, []interface{}, error) {
 		as    []interface{}
 		err   error
 	)
-	if sql, as, err = b.value.SQL(); err != nil {
+	if sql, as, err = r.render(b.value); err != nil {
 		return "", nil, err
 	}
 	args = append(args, as...)
 
-	if left, as, err = b.left.SQL(); err != nil {
+	if left, as, err = r.render(b.left); err != nil {
 		return "", nil, err
 	}
 	args = append(args, as...)
 
-	if right, as, err = b.right.SQL(); err != nil {
+	if right, as, err = r.render(b.right); err != nil {
 		return "", nil, err
 	}
 	args = append(args, as...)
 
-	return fmt.Sprintf("%s BETWEEN %s AND %s", sql, left, right), args, nil
+	return fmt.Sprintf("%s %s %s %s %s", sql, r.keyword("BETWEEN"), left, r.keyword("AND"), right), args, nil
 }
 
 type not struct {
@@ -181,14 +191,22 @@ func Not(right SQLer) SQLer {
 }
 
 func (n not) SQL() (string, []interface{}, error) {
+	return SQLDialect(n, Default)
+}
+
+func (n not) render(r *renderer) (string, []interface{}, error) {
 	if !acceptRelational(n.right) {
 		return "", nil, fmt.Errorf("not: %w", ErrSyntax)
 	}
-	right, args, err := n.right.SQL()
+	right, args, err := r.render(n.right)
 	if err != nil {
 		return "", nil, err
 	}
-	return fmt.Sprintf("NOT %s", right), args, nil
+	switch n.right.(type) {
+	case and, or, not:
+		right = "(" + right + ")"
+	}
+	return fmt.Sprintf("%s %s", r.keyword("NOT"), right), args, nil
 }
 
 type and struct {
@@ -204,6 +222,10 @@ func And(left, right SQLer) SQLer {
 }
 
 func (a and) SQL() (string, []interface{}, error) {
+	return SQLDialect(a, Default)
+}
+
+func (a and) render(r *renderer) (string, []interface{}, error) {
 	if !acceptRelational(a.left) {
 		return "", nil, fmt.Errorf("and(left): %w", ErrSyntax)
 	}
@@ -213,13 +235,13 @@ func (a and) SQL() (string, []interface{}, error) {
 	}
 
 	var args []interface{}
-	left, as, err := a.left.SQL()
+	left, as, err := r.render(a.left)
 	if err != nil {
 		return "", nil, err
 	}
 	args = append(args, as...)
 
-	right, as, err := a.right.SQL()
+	right, as, err := r.render(a.right)
 	if err != nil {
 		return "", nil, err
 	}
@@ -235,7 +257,7 @@ func (a and) SQL() (string, []interface{}, error) {
 		b.WriteString(left)
 	}
 
-	b.WriteString(" AND ")
+	b.WriteString(" " + r.keyword("AND") + " ")
 
 	switch a.right.(type) {
 	case and, or:
@@ -262,6 +284,10 @@ func Or(left, right SQLer) SQLer {
 }
 
 func (o or) SQL() (string, []interface{}, error) {
+	return SQLDialect(o, Default)
+}
+
+func (o or) render(r *renderer) (string, []interface{}, error) {
 	if !acceptRelational(o.left) {
 		return "", nil, fmt.Errorf("or(left): %w", ErrSyntax)
 	}
@@ -271,13 +297,13 @@ func (o or) SQL() (string, []interface{}, error) {
 	}
 
 	var args []interface{}
-	left, as, err := o.left.SQL()
+	left, as, err := r.render(o.left)
 	if err != nil {
 		return "", nil, err
 	}
 	args = append(args, as...)
 
-	right, as, err := o.right.SQL()
+	right, as, err := r.render(o.right)
 	if err != nil {
 		return "", nil, err
 	}
@@ -293,7 +319,7 @@ func (o or) SQL() (string, []interface{}, error) {
 		b.WriteString(left)
 	}
 
-	b.WriteString(" AND ")
+	b.WriteString(" " + r.keyword("OR") + " ")
 
 	switch o.right.(type) {
 	case and, or:
@@ -349,13 +375,17 @@ func NewCase() SQLer {
 }
 
 func (k kase) SQL() (string, []interface{}, error) {
+	return SQLDialect(k, Default)
+}
+
+func (k kase) render(r *renderer) (string, []interface{}, error) {
 	var (
 		b    strings.Builder
 		args []interface{}
 	)
-	b.WriteString("CASE ")
+	b.WriteString(r.keyword("CASE") + " ")
 	if k.expr != nil {
-		sql, as, err := k.expr.SQL()
+		sql, as, err := r.render(k.expr)
 		if err != nil {
 			return "", nil, err
 		}
@@ -364,15 +394,18 @@ func (k kase) SQL() (string, []interface{}, error) {
 		b.WriteString(" ")
 	}
 	for i := range k.test {
-		b.WriteString("WHEN ")
-		sql, as, err := k.test[i].SQL()
+		if i > 0 {
+			b.WriteString(" ")
+		}
+		b.WriteString(r.keyword("WHEN") + " ")
+		sql, as, err := r.render(k.test[i])
 		if err != nil {
 			return "", nil, err
 		}
 		args = append(args, as...)
 		b.WriteString(sql)
-		b.WriteString(" THEN ")
-		sql, as, err = k.csq[i].SQL()
+		b.WriteString(" " + r.keyword("THEN") + " ")
+		sql, as, err = r.render(k.csq[i])
 		if err != nil {
 			return "", nil, err
 		}
@@ -380,15 +413,15 @@ func (k kase) SQL() (string, []interface{}, error) {
 		b.WriteString(sql)
 	}
 	if k.alt != nil {
-		b.WriteString(" ELSE ")
-		sql, as, err := k.alt.SQL()
+		b.WriteString(" " + r.keyword("ELSE") + " ")
+		sql, as, err := r.render(k.alt)
 		if err != nil {
 			return "", nil, err
 		}
 		args = append(args, as...)
 		b.WriteString(sql)
 	}
-	b.WriteString(" END")
+	b.WriteString(" " + r.keyword("END"))
 	return b.String(), args, nil
 }
 
@@ -400,29 +433,53 @@ type any struct {
 	inner SQLer
 }
 
+// Any compares the left side of a comparison to each row returned by q, eg
+// GreaterThan(NewIdent("age"), Any(q)). The comparison is true if one of the
+// rows matches.
+func Any(q SQLer) SQLer {
+	return any{
+		inner: q,
+	}
+}
+
 func (a any) SQL() (string, []interface{}, error) {
-	sql, args, err := a.inner.SQL()
+	return SQLDialect(a, Default)
+}
+
+func (a any) render(r *renderer) (string, []interface{}, error) {
+	sql, args, err := r.subquery(a.inner)
 	if err != nil {
 		return "", nil, err
 	}
-	return fmt.Sprintf("ANY (%s)", sql), args, nil
+	return fmt.Sprintf("%s (%s)", r.keyword("ANY"), sql), args, nil
 }
 
 type all struct {
 	inner SQLer
 }
 
+// All is like Any but the comparison is true only if every row matches.
+func All(q SQLer) SQLer {
+	return all{
+		inner: q,
+	}
+}
+
 func (a all) SQL() (string, []interface{}, error) {
-	sql, args, err := a.inner.SQL()
+	return SQLDialect(a, Default)
+}
+
+func (a all) render(r *renderer) (string, []interface{}, error) {
+	sql, args, err := r.subquery(a.inner)
 	if err != nil {
 		return "", nil, err
 	}
-	return fmt.Sprintf("ALL (%s)", sql), args, nil
+	return fmt.Sprintf("%s (%s)", r.keyword("ALL"), sql), args, nil
 }
 
 func acceptRelational(part SQLer) bool {
 	switch part.(type) {
-	case compare, and, or:
+	case compare, and, or, not, between, exist:
 		return true
 	default:
 		return false
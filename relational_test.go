@@ -0,0 +1,101 @@
+package quel
+
+import (
+	"testing"
+)
+
+func TestLogical(t *testing.T) {
+	var (
+		first = Equal(NewIdent("first"), Arg("first", "roger"))
+		last  = Equal(NewIdent("last"), Arg("last", "lamotte"))
+		admin = Equal(NewIdent("role"), NewLiteral("admin"))
+	)
+	data := []struct {
+		Expr SQLer
+		Want string
+		Args []interface{}
+	}{
+		{
+			Expr: Or(first, last),
+			Want: "first = ? OR last = ?",
+			Args: []interface{}{"roger", "lamotte"},
+		},
+		{
+			Expr: And(first, last),
+			Want: "first = ? AND last = ?",
+			Args: []interface{}{"roger", "lamotte"},
+		},
+		{
+			Expr: Or(And(first, last), admin),
+			Want: "(first = ? AND last = ?) OR role = 'admin'",
+			Args: []interface{}{"roger", "lamotte"},
+		},
+		{
+			Expr: And(admin, Or(first, last)),
+			Want: "role = 'admin' AND (first = ? OR last = ?)",
+			Args: []interface{}{"roger", "lamotte"},
+		},
+	}
+	for _, d := range data {
+		compareQueries(t, d.Expr, d.Want, d.Args)
+	}
+}
+
+func TestIn(t *testing.T) {
+	data := []struct {
+		Expr SQLer
+		Want string
+		Args []interface{}
+	}{
+		{
+			Expr: In(NewIdent("id"), NewList(NewLiteral(1), NewLiteral(2))),
+			Want: "id IN (1, 2)",
+		},
+		{
+			Expr: In(NewIdent("id"), Raw("(SELECT user FROM roles)")),
+			Want: "id IN (SELECT user FROM roles)",
+		},
+		{
+			Expr: NotIn(NewIdent("id"), Arg("ids", []int{1, 2})),
+			Want: "id NOT IN ?",
+			Args: []interface{}{[]int{1, 2}},
+		},
+	}
+	for _, d := range data {
+		compareQueries(t, d.Expr, d.Want, d.Args)
+	}
+}
+
+func TestQuantified(t *testing.T) {
+	admins, _ := NewSelect("admins", SelectColumns("age"))
+	q := And(Equal(NewIdent("age"), All(admins)), GreaterThan(NewIdent("age"), Any(admins)))
+	compareQueries(t, q, "age = ALL (SELECT age FROM admins) AND age > ANY (SELECT age FROM admins)", nil)
+}
+
+func TestCase(t *testing.T) {
+	data := []struct {
+		Expr SQLer
+		Want string
+		Args []interface{}
+	}{
+		{
+			Expr: kase{
+				test: []SQLer{Equal(NewIdent("role"), NewLiteral("admin"))},
+				csq:  []SQLer{NewLiteral(1)},
+				alt:  NewLiteral(0),
+			},
+			Want: "CASE WHEN role = 'admin' THEN 1 ELSE 0 END",
+		},
+		{
+			Expr: kase{
+				expr: NewIdent("role"),
+				test: []SQLer{NewLiteral("admin"), NewLiteral("test")},
+				csq:  []SQLer{NewLiteral(1), NewLiteral(2)},
+			},
+			Want: "CASE role WHEN 'admin' THEN 1 WHEN 'test' THEN 2 END",
+		},
+	}
+	for _, d := range data {
+		compareQueries(t, d.Expr, d.Want, d.Args)
+	}
+}
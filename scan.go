@@ -0,0 +1,177 @@
+package quel
+
+import (
+	"database/sql"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+)
+
+type scanner struct {
+	strict bool
+}
+
+type ScanOption func(*scanner) error
+
+// ScanStrict makes the scan fail when a column of the result set can not be
+// mapped to a field of the destination struct.
+func ScanStrict() ScanOption {
+	return func(s *scanner) error {
+		s.strict = true
+		return nil
+	}
+}
+
+// ScanAll reads all the rows of rows into a slice of T and closes rows.
+//
+// If T is a struct (or a pointer to a struct), the columns of the result set
+// are mapped to its fields by the name given in their db tag or by their name
+// in lower case. Fields of embedded structs are promoted. Pointer fields are
+// set to nil when the column is NULL and fields implementing sql.Scanner are
+// given the raw value of the column. Otherwise, the result set should have
+// exactly one column that is scanned into T.
+func ScanAll[T interface{}](rows *sql.Rows, options ...ScanOption) ([]T, error) {
+	defer rows.Close()
+
+	scan, err := newScanFunc[T](rows, options)
+	if err != nil {
+		return nil, err
+	}
+	var list []T
+	for rows.Next() {
+		v, err := scan()
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, v)
+	}
+	return list, rows.Err()
+}
+
+// ScanOne reads the first row of rows into a T and closes rows. It returns
+// sql.ErrNoRows if rows is empty. See ScanAll for how columns are mapped.
+func ScanOne[T interface{}](rows *sql.Rows, options ...ScanOption) (T, error) {
+	defer rows.Close()
+
+	var zero T
+	scan, err := newScanFunc[T](rows, options)
+	if err != nil {
+		return zero, err
+	}
+	if !rows.Next() {
+		if err := rows.Err(); err != nil {
+			return zero, err
+		}
+		return zero, sql.ErrNoRows
+	}
+	v, err := scan()
+	if err != nil {
+		return zero, err
+	}
+	return v, rows.Close()
+}
+
+func newScanFunc[T interface{}](rows *sql.Rows, options []ScanOption) (func() (T, error), error) {
+	var s scanner
+	for _, opt := range options {
+		if err := opt(&s); err != nil {
+			return nil, err
+		}
+	}
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, err
+	}
+	var (
+		typ = reflect.TypeOf((*T)(nil)).Elem()
+		str = typ
+	)
+	if str.Kind() == reflect.Ptr {
+		str = str.Elem()
+	}
+	if str.Kind() != reflect.Struct || str == timeType || isScanner(str) {
+		if len(columns) != 1 {
+			return nil, fmt.Errorf("%w: %d columns can not be scanned into %s", ErrColumn, len(columns), typ)
+		}
+		scan := func() (T, error) {
+			var v T
+			return v, rows.Scan(&v)
+		}
+		return scan, nil
+	}
+	index, err := s.mapColumns(str, columns)
+	if err != nil {
+		return nil, err
+	}
+	scan := func() (T, error) {
+		var (
+			v    T
+			val  = reflect.New(str)
+			dest = make([]interface{}, len(index))
+		)
+		for i := range index {
+			if index[i] == nil {
+				dest[i] = new(interface{})
+				continue
+			}
+			dest[i] = allocFieldByIndex(val.Elem(), index[i]).Addr().Interface()
+		}
+		if err := rows.Scan(dest...); err != nil {
+			return v, err
+		}
+		if typ.Kind() == reflect.Ptr {
+			reflect.ValueOf(&v).Elem().Set(val)
+		} else {
+			reflect.ValueOf(&v).Elem().Set(val.Elem())
+		}
+		return v, nil
+	}
+	return scan, nil
+}
+
+func (s scanner) mapColumns(typ reflect.Type, columns []string) ([][]int, error) {
+	var (
+		fields = structFields(typ)
+		index  = make([][]int, len(columns))
+	)
+	for i, c := range columns {
+		for _, f := range fields {
+			if f.name == c {
+				index[i] = f.index
+				break
+			}
+			if index[i] == nil && strings.EqualFold(f.name, c) {
+				index[i] = f.index
+			}
+		}
+		if index[i] == nil && s.strict {
+			return nil, fmt.Errorf("%w: %q not mapped to a field of %s", ErrColumn, c, typ)
+		}
+	}
+	return index, nil
+}
+
+// allocFieldByIndex is like reflect.Value.FieldByIndex but allocates the nil
+// pointers to embedded structs on the way.
+func allocFieldByIndex(v reflect.Value, index []int) reflect.Value {
+	for i, x := range index {
+		if i > 0 && v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				v.Set(reflect.New(v.Type().Elem()))
+			}
+			v = v.Elem()
+		}
+		v = v.Field(x)
+	}
+	return v
+}
+
+var (
+	scannerType = reflect.TypeOf((*sql.Scanner)(nil)).Elem()
+	timeType    = reflect.TypeOf(time.Time{})
+)
+
+func isScanner(t reflect.Type) bool {
+	return reflect.PtrTo(t).Implements(scannerType)
+}
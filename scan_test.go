@@ -0,0 +1,113 @@
+package quel_test
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/midbel/quel"
+	"github.com/midbel/quel/exec"
+)
+
+type Audit struct {
+	Created string `db:"created"`
+}
+
+type User struct {
+	ID    int    `db:"id"`
+	Name  string `db:"name"`
+	Email *string
+	Role  sql.NullString `db:"role"`
+	Pass  string         `db:"-"`
+	*Audit
+}
+
+type audit struct {
+	Created string `db:"created"`
+}
+
+type Member struct {
+	*audit
+	Name string `db:"name"`
+}
+
+func TestScan(t *testing.T) {
+	fake := exec.NewFake()
+	db := fake.DB()
+	defer db.Close()
+
+	var (
+		ctx   = context.Background()
+		ex    = exec.New(db, nil)
+		email = "roger@localhost"
+	)
+	q, err := quel.NewSelect("users")
+	if err != nil {
+		t.Fatalf("error creating query! %s", err)
+	}
+	columns := []string{"id", "name", "email", "role", "created"}
+	fake.AddRows(columns, []driver.Value{int64(1), "roger", email, nil, "2021-01-01"}, []driver.Value{int64(2), "pierre", nil, "admin", "2021-01-02"})
+
+	rows, err := ex.Query(ctx, q)
+	if err != nil {
+		t.Fatalf("error executing query! %s", err)
+	}
+	users, err := quel.ScanAll[User](rows)
+	if err != nil {
+		t.Fatalf("error scanning rows! %s", err)
+	}
+	want := []User{
+		{ID: 1, Name: "roger", Email: &email, Audit: &Audit{Created: "2021-01-01"}},
+		{ID: 2, Name: "pierre", Role: sql.NullString{String: "admin", Valid: true}, Audit: &Audit{Created: "2021-01-02"}},
+	}
+	if !reflect.DeepEqual(users, want) {
+		t.Errorf("users mismatched!")
+		t.Logf("\twant: %+v", want)
+		t.Logf("\tgot:  %+v", users)
+	}
+
+	fake.AddRows([]string{"id", "name", "age"}, []driver.Value{int64(1), "roger", int64(42)})
+	rows, _ = ex.Query(ctx, q)
+	u, err := quel.ScanOne[*User](rows)
+	if err != nil {
+		t.Fatalf("error scanning row! %s", err)
+	}
+	if u.ID != 1 || u.Name != "roger" {
+		t.Errorf("unexpected user %+v", u)
+	}
+
+	fake.AddRows([]string{"id", "name", "age"}, []driver.Value{int64(1), "roger", int64(42)})
+	rows, _ = ex.Query(ctx, q)
+	if _, err := quel.ScanOne[User](rows, quel.ScanStrict()); !errors.Is(err, quel.ErrColumn) {
+		t.Errorf("expected ErrColumn for unmapped column, got %v", err)
+	}
+
+	fake.AddRows([]string{"name", "created"}, []driver.Value{"roger", "2021-01-01"})
+	rows, _ = ex.Query(ctx, q)
+	members, err := quel.ScanAll[Member](rows)
+	if err != nil {
+		t.Fatalf("error scanning rows! %s", err)
+	}
+	if want := []Member{{Name: "roger"}}; !reflect.DeepEqual(members, want) {
+		t.Errorf("members mismatched! want %+v, got %+v", want, members)
+	}
+
+	fake.AddRows([]string{"name"})
+	rows, _ = ex.Query(ctx, q)
+	if _, err := quel.ScanOne[string](rows); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("expected sql.ErrNoRows, got %v", err)
+	}
+
+	fake.AddRows([]string{"name"}, []driver.Value{"roger"}, []driver.Value{"pierre"})
+	rows, _ = ex.Query(ctx, q)
+	names, err := quel.ScanAll[string](rows)
+	if err != nil {
+		t.Fatalf("error scanning rows! %s", err)
+	}
+	if want := []string{"roger", "pierre"}; !reflect.DeepEqual(names, want) {
+		t.Errorf("names mismatched! want %v, got %v", want, names)
+	}
+}
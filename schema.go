@@ -0,0 +1,61 @@
+package quel
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Schema describes the tables of a database. It is given to Validate to
+// check the tables and the columns used by a query. The names of the tables
+// are case insensitive.
+type Schema struct {
+	tables []Table
+}
+
+func NewSchema(tables ...Table) (Schema, error) {
+	var s Schema
+	for _, t := range tables {
+		if _, ok := s.Table(t.name); ok {
+			return s, fmt.Errorf("schema: %w %q: table already defined", ErrIdent, t.name)
+		}
+		s.tables = append(s.tables, t)
+	}
+	return s, nil
+}
+
+// LoadSchema creates a Schema from the CREATE TABLE statements of ddl. The
+// other statements of ddl are parsed but ignored.
+func LoadSchema(ddl string, d Dialect) (Schema, error) {
+	list, err := ParseScript(ddl, d)
+	if err != nil {
+		return Schema{}, err
+	}
+	var tables []Table
+	for _, q := range list {
+		c, ok := q.(CreateTable)
+		if !ok {
+			continue
+		}
+		t, err := TableOf(c)
+		if err != nil {
+			return Schema{}, err
+		}
+		tables = append(tables, t)
+	}
+	return NewSchema(tables...)
+}
+
+// Table returns the table of s named name.
+func (s Schema) Table(name string) (Table, bool) {
+	for _, t := range s.tables {
+		if strings.EqualFold(t.name, name) {
+			return t, true
+		}
+	}
+	return Table{}, false
+}
+
+// Tables returns the tables of s in the order they were defined.
+func (s Schema) Tables() []Table {
+	return append([]Table{}, s.tables...)
+}
@@ -2,7 +2,6 @@ package quel
 
 import (
 	"fmt"
-	"strconv"
 	"strings"
 )
 
@@ -45,6 +44,28 @@ func SelectColumns(columns ...string) SelectOption {
 	}
 }
 
+// SelectStruct adds a column for each field of v, a struct or a pointer to a
+// struct. The names of the columns are given by the db tag of the fields (see
+// ScanAll) and are qualified by table when given. Fields tagged with "-" and
+// fields tagged with omitempty whose value is the zero value are skipped.
+func SelectStruct(v interface{}, table ...string) SelectOption {
+	return func(q *Select) error {
+		fields, _, err := structColumns(v)
+		if err != nil {
+			return err
+		}
+		var (
+			cs = make([]SQLer, len(fields))
+			nq = len(q.queries) - 1
+		)
+		for i, f := range fields {
+			cs[i] = NewIdent(f.name, table...)
+		}
+		q.queries[nq].columns = append(q.queries[nq].columns, cs...)
+		return nil
+	}
+}
+
 func SelectColumn(sql SQLer) SelectOption {
 	return func(q *Select) error {
 		n := len(q.queries) - 1
@@ -60,6 +81,9 @@ func SelectAlias(name string) SelectOption {
 		if !isValidIdentifier(name) {
 			return fmt.Errorf("alias: %w %q", ErrIdent, name)
 		}
+		if q.queries[0].table == nil {
+			return fmt.Errorf("alias: %w: no table given", ErrSyntax)
+		}
 		q.queries[0].table = Alias(name, q.queries[0].table)
 		return nil
 	}
@@ -68,7 +92,13 @@ func SelectAlias(name string) SelectOption {
 func SelectOrderBy(by ...SQLer) SelectOption {
 	return func(q *Select) error {
 		for i := range by {
+			if _, ok := by[i].(window); ok {
+				continue
+			}
 			o, ok := by[i].(orderby)
+			if ok && o.expr != nil {
+				continue
+			}
 			if !ok || !isValidIdentifier(o.column) {
 				return fmt.Errorf("ORDER BY: %w %q", ErrIdent, o.column)
 			}
@@ -111,6 +141,30 @@ func SelectWhere(where SQLer) SelectOption {
 	}
 }
 
+// SelectWindow adds a named window to the WINDOW clause of the query. spec is
+// created with Window. The window can then be used with WindowRef.
+func SelectWindow(name string, spec SQLer) SelectOption {
+	return func(q *Select) error {
+		if !isValidIdentifier(name) {
+			return fmt.Errorf("window: %w %q", ErrIdent, name)
+		}
+		w, ok := spec.(window)
+		if !ok || w.fn != nil {
+			return fmt.Errorf("window: %w: invalid specification", ErrSyntax)
+		}
+		if w.err != nil {
+			return w.err
+		}
+		for _, n := range q.windows {
+			if n, ok := n.(namedWindow); ok && n.name == name {
+				return fmt.Errorf("window: %w %q: already defined", ErrIdent, name)
+			}
+		}
+		q.windows = append(q.windows, namedWindow{name: name, spec: w})
+		return nil
+	}
+}
+
 func SelectDistinct() SelectOption {
 	return func(q *Select) error {
 		q.distinct = true
@@ -118,21 +172,85 @@ func SelectDistinct() SelectOption {
 	}
 }
 
-func SelectWith(name string, query Select, columns ...SQLer) SelectOption {
+// SelectWith adds a common table expression to the query. query is a Select,
+// a union or, for the engines supporting data-modifying statements in WITH,
+// an Insert, an Update or a Delete.
+func SelectWith(name string, query SQLer, columns ...SQLer) SelectOption {
 	return func(q *Select) error {
-		if !isValidIdentifier(name) {
-			return fmt.Errorf("with: %w %q", ErrIdent, name)
+		c, err := newCte(name, query, columns)
+		if err == nil {
+			q.ctes = append(q.ctes, c)
 		}
-		c := cte{
-			name:    name,
-			inner:   query,
-			columns: append([]SQLer{}, columns...),
+		return err
+	}
+}
+
+// SelectWithRecursive adds a recursive common table expression to the query.
+// Its body is the union of anchor and recursive with UNION ALL. If recursive
+// is nil, anchor is expected to be a union created with Union or UnionAll and
+// is used as is.
+func SelectWithRecursive(name string, anchor, recursive SQLer, columns ...SQLer) SelectOption {
+	return func(q *Select) error {
+		body := anchor
+		if recursive != nil {
+			u, err := newUnion(anchor, recursive, true)
+			if err != nil {
+				return fmt.Errorf("with recursive: %w", err)
+			}
+			body = u
+		} else if _, ok := anchor.(union); !ok {
+			return fmt.Errorf("with recursive: %w: union expected", ErrSyntax)
 		}
-		q.ctes = append(q.ctes, c)
+		c, err := newCte(name, body, columns)
+		if err != nil {
+			return err
+		}
+		x := c.(cte)
+		x.recursive = true
+		q.ctes = append(q.ctes, x)
 		return nil
 	}
 }
 
+const (
+	materializeAuto uint8 = iota
+	materializeAlways
+	materializeNever
+)
+
+type materialize struct {
+	inner SQLer
+	hint  uint8
+}
+
+// Materialized asks the database to compute the query of a common table
+// expression once. It is given instead of the query to SelectWith and the
+// other With options. The hint is only rendered for Postgres, SQLite and the
+// Default dialect.
+func Materialized(query SQLer) SQLer {
+	return materialize{
+		inner: query,
+		hint:  materializeAlways,
+	}
+}
+
+// NotMaterialized asks the database to inline the query of a common table
+// expression in the statement using it. See Materialized.
+func NotMaterialized(query SQLer) SQLer {
+	return materialize{
+		inner: query,
+		hint:  materializeNever,
+	}
+}
+
+func (m materialize) SQL() (string, []interface{}, error) {
+	return SQLDialect(m, Default)
+}
+
+func (m materialize) render(r *renderer) (string, []interface{}, error) {
+	return r.render(m.inner)
+}
+
 type jointype uint8
 
 const (
@@ -152,27 +270,43 @@ var joinops = map[jointype]string{
 }
 
 type cte struct {
-	name    string
-	inner   SQLer
-	columns []SQLer
+	name        string
+	inner       SQLer
+	columns     []SQLer
+	recursive   bool
+	materialize uint8
 }
 
 func (c cte) SQL() (string, []interface{}, error) {
+	return SQLDialect(c, Default)
+}
+
+func (c cte) render(r *renderer) (string, []interface{}, error) {
 	var (
 		b    strings.Builder
 		args []interface{}
 	)
-	b.WriteString(c.name)
-	b.WriteString("(")
-	as, err := writeSQL(&b, c.columns...)
-	if err != nil {
-		return "", nil, err
+	b.WriteString(r.ident(c.name))
+	if len(c.columns) > 0 {
+		b.WriteString("(")
+		as, err := writeSQL(r, &b, c.columns...)
+		if err != nil {
+			return "", nil, err
+		}
+		args = append(args, as...)
+		b.WriteString(")")
+	}
+	b.WriteString(" " + r.keyword("AS") + " ")
+	if r.is(Postgres) || r.is(SQLite) || r.is(Default) {
+		switch c.materialize {
+		case materializeAlways:
+			b.WriteString(r.keyword("MATERIALIZED") + " ")
+		case materializeNever:
+			b.WriteString(r.keyword("NOT MATERIALIZED") + " ")
+		}
 	}
-	args = append(args, as...)
-	b.WriteString(")")
-	b.WriteString(" AS ")
 	b.WriteString("(")
-	sql, as, err := c.inner.SQL()
+	sql, as, err := r.subquery(c.inner)
 	if err != nil {
 		return "", nil, err
 	}
@@ -183,6 +317,48 @@ func (c cte) SQL() (string, []interface{}, error) {
 	return b.String(), args, nil
 }
 
+func newCte(name string, query SQLer, columns []SQLer) (SQLer, error) {
+	if !isValidIdentifier(name) {
+		return nil, fmt.Errorf("with: %w %q", ErrIdent, name)
+	}
+	var hint uint8
+	if m, ok := query.(materialize); ok {
+		query, hint = m.inner, m.hint
+	}
+	switch query.(type) {
+	case Select, union, Insert, Update, Delete:
+	default:
+		return nil, fmt.Errorf("with: %w: invalid query", ErrSyntax)
+	}
+	c := cte{
+		name:        name,
+		inner:       query,
+		columns:     append([]SQLer{}, columns...),
+		materialize: hint,
+	}
+	return c, nil
+}
+
+// writeCtes writes the WITH clause of a statement if it has ctes.
+func writeCtes(r *renderer, b *strings.Builder, ctes []SQLer) ([]interface{}, error) {
+	if len(ctes) == 0 {
+		return nil, nil
+	}
+	b.WriteString(r.keyword("WITH") + " ")
+	for _, c := range ctes {
+		if c, ok := c.(cte); ok && c.recursive {
+			b.WriteString(r.keyword("RECURSIVE") + " ")
+			break
+		}
+	}
+	args, err := writeSQL(r, b, ctes...)
+	if err != nil {
+		return nil, err
+	}
+	b.WriteString(r.sep())
+	return args, nil
+}
+
 type query struct {
 	columns []SQLer
 	table   SQLer
@@ -190,9 +366,18 @@ type query struct {
 	join    jointype
 }
 
+// isSource reports whether sql can be used as the source of a statement. A
+// Select should be aliased to be used as a derived table.
+func isSource(sql SQLer) bool {
+	if _, ok := sql.(Select); ok {
+		return false
+	}
+	return isJoinable(sql)
+}
+
 func isJoinable(sql SQLer) bool {
 	switch sql := sql.(type) {
-	case Select, ident:
+	case Select, ident, Table:
 		return true
 	case alias:
 		return isJoinable(sql.SQLer)
@@ -205,6 +390,74 @@ func Using(list ...SQLer) SQLer {
 	return NewList(list...)
 }
 
+// writeSources writes kw, if any, followed by the tables of a statement.
+// Tables that are not joined are separated by commas, the others are preceded
+// by their join operator and followed by their condition. When formatted, kw
+// and the join operators are padded to the same width to align the tables.
+func writeSources(r *renderer, b *strings.Builder, kw string, queries []query) ([]interface{}, error) {
+	width := len(kw)
+	for _, q := range queries {
+		if q.join != none && len(joinops[q.join]) > width {
+			width = len(joinops[q.join])
+		}
+	}
+	if kw != "" {
+		b.WriteString(r.align(kw, width) + " ")
+	}
+	var args []interface{}
+	for i, q := range queries {
+		if i > 0 && q.join == none {
+			b.WriteString(", ")
+		}
+		if i > 0 && q.join != none {
+			b.WriteString(r.sep() + r.align(joinops[q.join], width) + " ")
+		}
+		sql, as, err := r.render(q.table)
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, as...)
+		b.WriteString(sql)
+
+		if q.join == none {
+			continue
+		}
+		sql, as, err = r.render(q.cdt)
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, as...)
+		switch q.cdt.(type) {
+		case and, or, compare:
+			b.WriteString(" " + r.keyword("ON") + " ")
+			b.WriteString(sql)
+		case list:
+			b.WriteString(" " + r.keyword("USING") + " (")
+			b.WriteString(sql)
+			b.WriteString(")")
+		default:
+			return nil, fmt.Errorf("join: %w", ErrSyntax)
+		}
+	}
+	return args, nil
+}
+
+// conjunction returns the predicates joined by AND. nil predicates are
+// skipped.
+func conjunction(preds ...SQLer) SQLer {
+	var res SQLer
+	for _, p := range preds {
+		switch {
+		case p == nil:
+		case res == nil:
+			res = p
+		default:
+			res = And(res, p)
+		}
+	}
+	return res
+}
+
 type Select struct {
 	ctes     []SQLer
 	queries  []query
@@ -212,18 +465,23 @@ type Select struct {
 	orderby  []SQLer
 	groupby  []SQLer
 	having   SQLer
+	windows  []SQLer
 	limit    int
 	offset   int
 	distinct bool
 }
 
+// NewSelect creates a query selecting from table. Without table, the query has
+// no FROM clause, eg SELECT NOW().
 func NewSelect(table string, options ...SelectOption) (Select, error) {
 	var (
 		base Select
 		err  error
 		q    query
 	)
-	q.table = NewIdent(table)
+	if table != "" {
+		q.table = NewIdent(table)
+	}
 	base.queries = append(base.queries, q)
 
 	for _, opt := range options {
@@ -286,94 +544,101 @@ func (s Select) join(jt jointype, source, cdt SQLer, options ...SelectOption) (S
 	return base, err
 }
 
+// Apply returns a copy of s with options applied to it. s is left unmodified.
+func (s Select) Apply(options ...SelectOption) (Select, error) {
+	base := s
+	base.ctes = append([]SQLer{}, s.ctes...)
+	base.queries = make([]query, len(s.queries))
+	for i, q := range s.queries {
+		q.columns = append([]SQLer{}, q.columns...)
+		base.queries[i] = q
+	}
+	base.groupby = append([]SQLer{}, s.groupby...)
+	base.orderby = append([]SQLer{}, s.orderby...)
+	base.windows = append([]SQLer{}, s.windows...)
+	for _, opt := range options {
+		if err := opt(&base); err != nil {
+			return s, err
+		}
+	}
+	return base, nil
+}
+
 func (s Select) Exists() SQLer {
 	return Exists(s)
 }
 
 func (s Select) SQL() (string, []interface{}, error) {
+	return SQLDialect(s, Default)
+}
+
+func (s Select) SQLDialect(d Dialect) (string, []interface{}, error) {
+	return SQLDialect(s, d)
+}
+
+func (s Select) render(r *renderer) (string, []interface{}, error) {
+	if err := checkWindows(s); err != nil {
+		return "", nil, err
+	}
 	var (
 		b    strings.Builder
 		args []interface{}
 	)
-	if len(s.ctes) > 0 {
-		b.WriteString("WITH ")
-		as, err := writeSQL(&b, s.ctes...)
-		if err != nil {
-			return "", nil, err
-		}
-		args = append(args, as...)
-		b.WriteString(" ")
+	args, err := writeCtes(r, &b, s.ctes)
+	if err != nil {
+		return "", nil, err
 	}
-	b.WriteString("SELECT ")
+	b.WriteString(r.keyword("SELECT") + " ")
 	if s.distinct {
-		b.WriteString("DISTINCT ")
+		b.WriteString(r.keyword("DISTINCT") + " ")
 	}
-	for i, q := range s.queries {
-		if i > 0 {
-			b.WriteString(", ")
-		}
+	if s.columnsCount() == 0 {
+		b.WriteString("*")
+	}
+	var n int
+	for _, q := range s.queries {
 		if len(q.columns) == 0 {
-			b.WriteString("*")
 			continue
 		}
-		as, err := writeSQL(&b, q.columns...)
+		if n > 0 {
+			b.WriteString(", ")
+		}
+		n++
+		as, err := writeSQL(r, &b, q.columns...)
 		if err != nil {
 			return "", nil, err
 		}
 		args = append(args, as...)
 	}
-	b.WriteString(" FROM ")
-	for i, q := range s.queries {
-		if i > 0 && q.join != none {
-			b.WriteString(" ")
-			b.WriteString(joinops[q.join])
-			b.WriteString(" ")
-		}
-		sql, as, err := q.table.SQL()
+	if s.queries[0].table != nil {
+		b.WriteString(r.sep())
+		as, err := writeSources(r, &b, "FROM", s.queries)
 		if err != nil {
 			return "", nil, err
 		}
 		args = append(args, as...)
-		b.WriteString(sql)
-
-		if q.join != none {
-			sql, as, err = q.cdt.SQL()
-			if err != nil {
-				return "", nil, err
-			}
-			args = append(args, as...)
-			switch q.cdt.(type) {
-			case and, or, compare:
-				b.WriteString(" ON ")
-				b.WriteString(sql)
-			case list:
-				b.WriteString(" USING (")
-				b.WriteString(sql)
-				b.WriteString(")")
-			default:
-				return "", nil, fmt.Errorf("join: %w", ErrSyntax)
-			}
-		}
+	} else if len(s.queries) > 1 {
+		return "", nil, fmt.Errorf("%w: no table to join", ErrSyntax)
 	}
 	if s.where != nil {
-		sql, as, err := s.where.SQL()
+		sql, as, err := r.render(s.where)
 		if err != nil {
 			return "", nil, err
 		}
-		b.WriteString(" WHERE ")
+		b.WriteString(r.clause("WHERE"))
 		b.WriteString(sql)
 		args = append(args, as...)
 	}
 	if len(s.groupby) > 0 {
-		b.WriteString(" GROUP BY ")
-		as, err := writeSQL(&b, s.groupby...)
+		b.WriteString(r.clause("GROUP BY"))
+		as, err := writeSQL(r, &b, s.groupby...)
 		if err != nil {
 			return "", nil, err
 		}
 		args = append(args, as...)
 		if s.having != nil {
-			b.WriteString(" HAVING ")
-			sql, as, err := s.having.SQL()
+			b.WriteString(r.clause("HAVING"))
+			sql, as, err := r.render(s.having)
 			if err != nil {
 				return "", nil, err
 			}
@@ -381,21 +646,25 @@ func (s Select) SQL() (string, []interface{}, error) {
 			b.WriteString(sql)
 		}
 	}
-	if len(s.orderby) > 0 {
-		b.WriteString(" ORDER BY ")
-		as, err := writeSQL(&b, s.orderby...)
+	if len(s.windows) > 0 {
+		b.WriteString(r.clause("WINDOW"))
+		as, err := writeSQL(r, &b, s.windows...)
 		if err != nil {
 			return "", nil, err
 		}
 		args = append(args, as...)
 	}
-	if s.limit > 0 {
-		b.WriteString(" LIMIT ")
-		b.WriteString(strconv.Itoa(s.limit))
+	if len(s.orderby) > 0 {
+		b.WriteString(r.clause("ORDER BY"))
+		as, err := writeSQL(r, &b, s.orderby...)
+		if err != nil {
+			return "", nil, err
+		}
+		args = append(args, as...)
 	}
-	if s.offset > 0 {
-		b.WriteString(" OFFSET ")
-		b.WriteString(strconv.Itoa(s.offset))
+	if str := r.Paginate(s.limit, s.offset); str != "" {
+		b.WriteString(r.sep())
+		b.WriteString(r.keyword(str))
 	}
 	return b.String(), args, nil
 }
@@ -443,23 +712,29 @@ func newUnion(left, right SQLer, all bool) (SQLer, error) {
 }
 
 func (u union) SQL() (string, []interface{}, error) {
+	return SQLDialect(u, Default)
+}
+
+func (u union) render(r *renderer) (string, []interface{}, error) {
 	var (
 		b    strings.Builder
 		args []interface{}
 	)
-	left, as, err := u.left.SQL()
+	left, as, err := r.render(u.left)
 	if err != nil {
 		return "", nil, err
 	}
 	args = append(args, as...)
 	b.WriteString(left)
 
-	b.WriteString(" UNION ")
+	b.WriteString(r.sep())
+	b.WriteString(r.keyword("UNION"))
 	if u.all {
-		b.WriteString("ALL ")
+		b.WriteString(" " + r.keyword("ALL"))
 	}
+	b.WriteString(r.sep())
 
-	right, as, err := u.right.SQL()
+	right, as, err := r.render(u.right)
 	if err != nil {
 		return "", nil, err
 	}
@@ -478,21 +753,57 @@ func Exists(s SQLer) SQLer {
 }
 
 func (e exist) SQL() (string, []interface{}, error) {
+	return SQLDialect(e, Default)
+}
+
+func (e exist) render(r *renderer) (string, []interface{}, error) {
 	var str string
-	sql, args, err := e.inner.SQL()
+	sql, args, err := r.subquery(e.inner)
 	if err == nil {
-		str = fmt.Sprintf("EXISTS %s", sql)
+		str = fmt.Sprintf("%s (%s)", r.keyword("EXISTS"), sql)
 	}
 	return str, args, err
 }
 
 type orderby struct {
 	column string
+	expr   SQLer
 	order  string
 }
 
+// orderExpr returns the sort key of an ORDER BY clause made of an expression
+// instead of a column, eg COUNT(id) or the position of a column.
+func orderExpr(expr SQLer, order string) SQLer {
+	return orderby{
+		expr:  expr,
+		order: order,
+	}
+}
+
 func (o orderby) SQL() (string, []interface{}, error) {
-	return fmt.Sprintf("%s %s", o.column, o.order), nil, nil
+	return SQLDialect(o, Default)
+}
+
+func (o orderby) render(r *renderer) (string, []interface{}, error) {
+	if o.expr == nil {
+		return fmt.Sprintf("%s %s", r.ident(o.column), r.keyword(o.order)), nil, nil
+	}
+	sql, args, err := r.render(o.expr)
+	if err != nil {
+		return "", nil, err
+	}
+	return fmt.Sprintf("%s %s", sql, r.keyword(o.order)), args, nil
+}
+
+// AscExpr returns the ascending sort key made of expr, eg a Column of a Table
+// or a function.
+func AscExpr(expr SQLer) SQLer {
+	return orderExpr(expr, "ASC")
+}
+
+// DescExpr returns the descending sort key made of expr. See AscExpr.
+func DescExpr(expr SQLer) SQLer {
+	return orderExpr(expr, "DESC")
 }
 
 func Asc(column string) SQLer {
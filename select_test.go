@@ -1,6 +1,7 @@
 package quel
 
 import (
+	"errors"
 	"testing"
 )
 
@@ -8,6 +9,197 @@ func TestSelect(t *testing.T) {
 	t.Run("simple", testSimpleSelect)
 	t.Run("join", testJoinSelect)
 	t.Run("subquery", testSubquerySelect)
+	t.Run("struct", testStructSelect)
+	t.Run("window", testWindowSelect)
+	t.Run("cte", testCteSelect)
+}
+
+func testCteSelect(t *testing.T) {
+	anchor, _ := NewSelect("categories", SelectColumns("id", "parent"), SelectWhere(IsNullTest(NewIdent("parent"))))
+	recursive, _ := NewSelect("categories", SelectColumn(NewIdent("id", "c")), SelectColumn(NewIdent("parent", "c")), SelectAlias("c"))
+	recursive, _ = recursive.LeftInnerJoin(Alias("t", NewIdent("tree")), Equal(NewIdent("parent", "c"), NewIdent("id", "t")))
+	actives, _ := NewSelect("users", SelectColumns("id"), SelectWhere(Equal(NewIdent("active"), Arg("active", true))))
+
+	data := []struct {
+		Options []SelectOption
+		Dialect Dialect
+		Want    string
+		Args    []interface{}
+	}{
+		{
+			Options: []SelectOption{
+				SelectWithRecursive("tree", anchor, recursive, NewIdent("id"), NewIdent("parent")),
+				SelectColumns("id"),
+			},
+			Dialect: Default,
+			Want:    "WITH RECURSIVE tree(id, parent) AS (SELECT id, parent FROM categories WHERE parent IS NULL UNION ALL SELECT c.id, c.parent FROM categories AS c INNER JOIN tree AS t ON c.parent = t.id) SELECT id FROM tree",
+		},
+		{
+			Options: []SelectOption{
+				SelectWith("actives", Materialized(actives)),
+				SelectWithRecursive("tree", anchor, recursive),
+				SelectColumns("id"),
+			},
+			Dialect: Postgres,
+			Want:    `WITH RECURSIVE "actives" AS MATERIALIZED (SELECT "id" FROM "users" WHERE "active" = $1), "tree" AS (SELECT "id", "parent" FROM "categories" WHERE "parent" IS NULL UNION ALL SELECT "c"."id", "c"."parent" FROM "categories" AS "c" INNER JOIN "tree" AS "t" ON "c"."parent" = "t"."id") SELECT "id" FROM "tree"`,
+			Args:    []interface{}{true},
+		},
+		{
+			Options: []SelectOption{
+				SelectWith("actives", NotMaterialized(actives)),
+				SelectColumns("id"),
+			},
+			Dialect: MySQL,
+			Want:    "WITH `actives` AS (SELECT `id` FROM `users` WHERE `active` = ?) SELECT `id` FROM `tree`",
+			Args:    []interface{}{true},
+		},
+	}
+	for _, d := range data {
+		q, err := NewSelect("tree", d.Options...)
+		if err != nil {
+			t.Errorf("error creating query! %s", err)
+			continue
+		}
+		compareDialect(t, q, d.Dialect, d.Want, d.Args)
+	}
+
+	body, _ := Union(anchor, recursive)
+	q, err := NewSelect("tree", SelectWithRecursive("tree", body, nil), SelectColumns("id"))
+	if err != nil {
+		t.Fatalf("error creating query! %s", err)
+	}
+	compareQueries(t, q, "WITH RECURSIVE tree AS (SELECT id, parent FROM categories WHERE parent IS NULL UNION SELECT c.id, c.parent FROM categories AS c INNER JOIN tree AS t ON c.parent = t.id) SELECT id FROM tree", nil)
+
+	if _, err := NewSelect("tree", SelectWithRecursive("tree", anchor, nil)); err == nil {
+		t.Errorf("expected error when recursive query is missing")
+	}
+	if _, err := NewSelect("tree", SelectWithRecursive("tree", anchor, actives)); err == nil {
+		t.Errorf("expected error when columns count mismatched")
+	}
+}
+
+func testWindowSelect(t *testing.T) {
+	data := []struct {
+		Options []SelectOption
+		Want    string
+	}{
+		{
+			Options: []SelectOption{
+				SelectColumns("id"),
+				SelectColumn(Alias("rn", Over(RowNumber(), Partition(NewIdent("user")), Order(Desc("created"))))),
+			},
+			Want: "SELECT id, ROW_NUMBER() OVER (PARTITION BY user ORDER BY created DESC) AS rn FROM orders",
+		},
+		{
+			Options: []SelectOption{
+				SelectColumn(Over(Sum(NewIdent("amount")), Order(Asc("created")), Rows(Preceding(3), CurrentRow()))),
+				SelectColumn(Over(Lag(NewIdent("amount"), 1), Order(NewIdent("created")))),
+			},
+			Want: "SELECT SUM(amount) OVER (ORDER BY created ASC ROWS BETWEEN 3 PRECEDING AND CURRENT ROW), LAG(amount, 1) OVER (ORDER BY created) FROM orders",
+		},
+		{
+			Options: []SelectOption{
+				SelectColumns("id"),
+				SelectOrderBy(Over(NTile(4), Order(NewIdent("amount")), Range(UnboundedPreceding()))),
+			},
+			Want: "SELECT id FROM orders ORDER BY NTILE(4) OVER (ORDER BY amount RANGE UNBOUNDED PRECEDING)",
+		},
+	}
+	for _, d := range data {
+		q, err := NewSelect("orders", d.Options...)
+		if err != nil {
+			t.Errorf("error creating query! %s", err)
+			continue
+		}
+		compareQueries(t, q, d.Want, nil)
+	}
+	named := []SelectOption{
+		SelectColumns("id"),
+		SelectColumn(Over(Rank(), WindowRef("w"))),
+		SelectColumn(Over(Sum(NewIdent("amount")), WindowRef("x"), Rows(UnboundedPreceding(), CurrentRow()))),
+		SelectWindow("w", Window(Partition(NewIdent("user")), Order(Desc("amount")))),
+		SelectWindow("x", Window(WindowRef("w"))),
+		SelectOrderBy(Asc("id")),
+	}
+	q, err := NewSelect("orders", named...)
+	if err != nil {
+		t.Fatalf("error creating query! %s", err)
+	}
+	want := "SELECT id, RANK() OVER w, SUM(amount) OVER (x ROWS BETWEEN UNBOUNDED PRECEDING AND CURRENT ROW) FROM orders WINDOW w AS (PARTITION BY user ORDER BY amount DESC), x AS (w) ORDER BY id ASC"
+	compareQueries(t, q, want, nil)
+
+	q, err = NewSelect("orders", SelectColumn(Over(Rank(), WindowRef("w"))))
+	if err != nil {
+		t.Fatalf("error creating query! %s", err)
+	}
+	if _, _, err := q.SQL(); err == nil {
+		t.Errorf("expected error when using an undefined window")
+	}
+	if _, err := NewSelect("orders", SelectWindow("w", Window()), SelectWindow("w", Window())); err == nil {
+		t.Errorf("expected error when defining a window twice")
+	}
+	if _, err := NewSelect("orders", SelectWindow("w", Over(Rank()))); err == nil {
+		t.Errorf("expected error when defining a window with a function")
+	}
+
+	invalid := []SQLer{
+		Over(NewIdent("id")),
+		Over(Rank(), Rows()),
+		Over(Rank(), Rows(Preceding(1), CurrentRow(), Following(1))),
+		Over(Rank(), Rows(NewIdent("id"))),
+		Over(Rank(), Rows(Preceding(-1))),
+	}
+	for _, w := range invalid {
+		if _, _, err := w.SQL(); err == nil {
+			t.Errorf("expected error for invalid window")
+		}
+	}
+}
+
+func testStructSelect(t *testing.T) {
+	type audit struct {
+		Created string `db:"created"`
+	}
+	type user struct {
+		ID    int    `db:"id"`
+		Name  string `db:"name"`
+		Email string `db:"email,omitempty"`
+		Pass  string `db:"-"`
+		Role  string
+		audit
+	}
+	data := []struct {
+		Options []SelectOption
+		Want    string
+	}{
+		{
+			Options: []SelectOption{
+				SelectStruct(user{}),
+			},
+			Want: "SELECT id, name, role, created FROM users",
+		},
+		{
+			Options: []SelectOption{
+				SelectStruct(&user{Email: "roger@localhost"}, "u"),
+				SelectAlias("u"),
+			},
+			Want: "SELECT u.id, u.name, u.email, u.role, u.created FROM users AS u",
+		},
+	}
+	for _, d := range data {
+		q, err := NewSelect("users", d.Options...)
+		if err != nil {
+			t.Errorf("error creating query! %s", err)
+			continue
+		}
+		compareQueries(t, q, d.Want, nil)
+	}
+	if _, err := NewSelect("users", SelectStruct(42)); err == nil {
+		t.Errorf("expected error when giving a non struct value")
+	}
+	if _, err := NewSelect("users", SelectStruct(nil)); !errors.Is(err, ErrArg) {
+		t.Errorf("expected ErrArg when giving a nil value, got %v", err)
+	}
 }
 
 func testSubquerySelect(t *testing.T) {
@@ -109,6 +301,36 @@ func testSimpleSelect(t *testing.T) {
 			Table: "users",
 			Want:  "SELECT COUNT(id) FROM users GROUP BY active",
 		},
+		{
+			Options: []SelectOption{
+				SelectColumns("id"),
+				SelectWhere(Between(NewIdent("age"), NewLiteral(18), NewLiteral(65))),
+			},
+			Table: "users",
+			Want:  "SELECT id FROM users WHERE age BETWEEN 18 AND 65",
+		},
+		{
+			Options: []SelectOption{
+				SelectColumns("id"),
+				SelectWhere(Not(Equal(NewIdent("role"), Arg("role", "admin")))),
+			},
+			Table: "users",
+			Want:  "SELECT id FROM users WHERE NOT role = ?",
+			Args:  []interface{}{"admin"},
+		}, {
+			Options: []SelectOption{
+				SelectColumn(Count(Distinct(NewIdent("role")))),
+				SelectColumn(Negate(Add(NewIdent("a"), NewIdent("b")))),
+			},
+			Table: "users",
+			Want:  "SELECT COUNT(DISTINCT role), -(a + b) FROM users",
+		},
+		{
+			Options: []SelectOption{
+				SelectColumn(Func("NOW")),
+			},
+			Want: "SELECT NOW()",
+		},
 	}
 	for _, d := range data {
 		q, err := NewSelect(d.Table, d.Options...)
@@ -0,0 +1,285 @@
+package quel
+
+import (
+	"fmt"
+)
+
+type TableOption func(*Table) error
+
+// TableColumn adds a column to the table. kind and options are the same as
+// the ones given to CreateColumn.
+func TableColumn(name string, kind SQLer, options ...ColumnOption) TableOption {
+	return func(t *Table) error {
+		for _, c := range t.columns {
+			if c.name == name {
+				return fmt.Errorf("table: %w %q: already defined", ErrColumn, name)
+			}
+		}
+		def, err := newColumnDef(name, kind, options)
+		if err == nil {
+			t.columns = append(t.columns, def)
+		}
+		return err
+	}
+}
+
+// TableConstraint adds a table constraint created with PrimaryKey, Unique,
+// Check or ForeignKey. The columns of a primary key are not nullable.
+func TableConstraint(cst SQLer) TableOption {
+	return func(t *Table) error {
+		x, ok := cst.(constraint)
+		if !ok {
+			return fmt.Errorf("constraint: %w: invalid constraint", ErrSyntax)
+		}
+		if x.err != nil {
+			return x.err
+		}
+		t.constraints = append(t.constraints, cst)
+		return nil
+	}
+}
+
+// Table describes a table of the database and its columns. It can be used as
+// the source of a query and be joined like the identifiers created by
+// NewIdent.
+//
+// Typed tables are declared by embedding a Table in a struct having a Column
+// field for each of its columns:
+//
+//	type usersTable struct {
+//		Table
+//		ID    Column
+//		Email Column
+//	}
+type Table struct {
+	name        string
+	alias       string
+	columns     []columnDef
+	constraints []SQLer
+	err         error
+}
+
+func NewTable(name string, options ...TableOption) (Table, error) {
+	var (
+		t   Table
+		err error
+	)
+	t.name = name
+	if !isValidIdentifier(name) {
+		return t, fmt.Errorf("table: %w %q", ErrIdent, name)
+	}
+	for _, opt := range options {
+		if err = opt(&t); err != nil {
+			break
+		}
+	}
+	return t, err
+}
+
+// TableOf returns the Table described by c. It fails if c creates its table
+// from a query.
+func TableOf(c CreateTable) (Table, error) {
+	var t Table
+	if c.query != nil {
+		return t, fmt.Errorf("table: %w: columns are not known", ErrSyntax)
+	}
+	id, ok := c.table.(ident)
+	if !ok {
+		return t, fmt.Errorf("table: %w: invalid name", ErrIdent)
+	}
+	t.name = id.name
+	for _, col := range c.columns {
+		if def, ok := col.(columnDef); ok {
+			t.columns = append(t.columns, def)
+		}
+	}
+	t.constraints = append(t.constraints, c.constraints...)
+	return t, nil
+}
+
+func (t Table) Name() string {
+	return t.name
+}
+
+// As returns a copy of t aliased with alias. The columns returned by the copy
+// are qualified with alias instead of the name of the table.
+func (t Table) As(alias string) Table {
+	t.alias = alias
+	if t.err == nil && !isValidIdentifier(alias) {
+		t.err = fmt.Errorf("alias: %w %q", ErrIdent, alias)
+	}
+	return t
+}
+
+// Column returns the column of t named name. Using a column that is not
+// defined by t gives an error when the query is rendered.
+func (t Table) Column(name string) Column {
+	for _, c := range t.columns {
+		if c.name == name {
+			return t.newColumn(c)
+		}
+	}
+	return Column{
+		name:  name,
+		table: t.qualifier(),
+		err:   fmt.Errorf("table %s: %w %q", t.name, ErrColumn, name),
+	}
+}
+
+// Columns returns all the columns of t in the order they were defined.
+func (t Table) Columns() []Column {
+	list := make([]Column, 0, len(t.columns))
+	for _, c := range t.columns {
+		list = append(list, t.newColumn(c))
+	}
+	return list
+}
+
+// Select creates a query selecting from t. The columns of t can be added with
+// SelectColumn.
+func (t Table) Select(options ...SelectOption) (Select, error) {
+	var (
+		base Select
+		err  error
+	)
+	base.queries = append(base.queries, query{table: t})
+	for _, opt := range options {
+		if err = opt(&base); err != nil {
+			break
+		}
+	}
+	return base, err
+}
+
+// Create returns the CREATE TABLE statement of t. Only CreateConstraint and
+// CreateIfNotExists should be given as options.
+func (t Table) Create(options ...CreateTableOption) (CreateTable, error) {
+	var c CreateTable
+	c.table = NewIdent(t.name)
+	for _, def := range t.columns {
+		c.columns = append(c.columns, def)
+	}
+	c.constraints = append(c.constraints, t.constraints...)
+	for _, opt := range options {
+		if err := opt(&c); err != nil {
+			return c, err
+		}
+	}
+	if len(c.columns) != len(t.columns) {
+		return c, fmt.Errorf("%w: columns can not be added to table %s", ErrSyntax, t.name)
+	}
+	if len(c.columns) == 0 {
+		return c, fmt.Errorf("%w: no columns given", ErrSyntax)
+	}
+	return c, nil
+}
+
+func (t Table) SQL() (string, []interface{}, error) {
+	return SQLDialect(t, Default)
+}
+
+func (t Table) render(r *renderer) (string, []interface{}, error) {
+	if t.err != nil {
+		return "", nil, t.err
+	}
+	var sql SQLer = NewIdent(t.name)
+	if t.alias != "" {
+		sql = Alias(t.alias, sql)
+	}
+	return r.render(sql)
+}
+
+func (t Table) qualifier() string {
+	if t.alias != "" {
+		return t.alias
+	}
+	return t.name
+}
+
+func (t Table) newColumn(c columnDef) Column {
+	return Column{
+		name:     c.name,
+		table:    t.qualifier(),
+		kind:     c.kind,
+		nullable: c.null != notNullable && !c.primary && !t.isPrimary(c.name),
+		err:      t.err,
+	}
+}
+
+func (t Table) isPrimary(column string) bool {
+	for _, c := range t.constraints {
+		x, ok := c.(constraint)
+		if !ok || x.kind != constraintPrimary {
+			continue
+		}
+		for _, name := range x.columns {
+			if name == column {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Column is a column of a Table. It is rendered qualified by the alias of its
+// table or, if it has none, by the name of its table. A Column can be used
+// everywhere an identifier created by NewIdent is accepted. The options taking
+// the name of a column as a string have a variant accepting a Column:
+// SelectColumn, InsertColumn, UpdateSet, AscExpr and DescExpr.
+type Column struct {
+	name     string
+	table    string
+	kind     SQLer
+	nullable bool
+	err      error
+}
+
+// Name returns the name of the column without its qualifier.
+func (c Column) Name() string {
+	return c.name
+}
+
+// Parents returns the qualifier of the column.
+func (c Column) Parents() []string {
+	return []string{c.table}
+}
+
+// Type returns the type of the column as given to TableColumn.
+func (c Column) Type() SQLer {
+	return c.kind
+}
+
+// Nullable reports whether the column can be NULL. Columns are nullable
+// unless they are declared with ColumnNotNull or ColumnPrimaryKey or are part
+// of the primary key of their table.
+func (c Column) Nullable() bool {
+	return c.nullable
+}
+
+func (c Column) Alias(name string) SQLer {
+	return Alias(name, c)
+}
+
+func (c Column) SQL() (string, []interface{}, error) {
+	return SQLDialect(c, Default)
+}
+
+func (c Column) render(r *renderer) (string, []interface{}, error) {
+	if c.err != nil {
+		return "", nil, c.err
+	}
+	return r.render(NewIdent(c.name, c.table))
+}
+
+// columnName returns the name of column, an identifier or a Column, without
+// its qualifier.
+func columnName(column SQLer) (string, error) {
+	if c, ok := column.(Column); ok && c.err != nil {
+		return "", c.err
+	}
+	id, ok := column.(IdentNode)
+	if !ok || !isValidIdentifier(id.Name()) {
+		return "", fmt.Errorf("column: %w: identifier expected", ErrIdent)
+	}
+	return id.Name(), nil
+}
@@ -0,0 +1,124 @@
+package quel
+
+import (
+	"testing"
+)
+
+type usersTable struct {
+	Table
+	ID    Column
+	Email Column
+}
+
+func newUsersTable(t *testing.T, alias string) usersTable {
+	t.Helper()
+	tb, err := NewTable("users",
+		TableColumn("id", TypeInt(), ColumnPrimaryKey()),
+		TableColumn("email", TypeVarchar(255)),
+	)
+	if err != nil {
+		t.Fatalf("error creating table! %s", err)
+	}
+	if alias != "" {
+		tb = tb.As(alias)
+	}
+	return usersTable{
+		Table: tb,
+		ID:    tb.Column("id"),
+		Email: tb.Column("email"),
+	}
+}
+
+func TestTable(t *testing.T) {
+	t.Run("select", func(t *testing.T) {
+		users := newUsersTable(t, "")
+		q, err := users.Select(SelectColumn(users.ID), SelectColumn(users.Email), SelectWhere(Equal(users.ID, Arg("id", 1))))
+		if err != nil {
+			t.Fatalf("error creating query! %s", err)
+		}
+		compareQueries(t, q, "SELECT users.id, users.email FROM users WHERE users.id = ?", []interface{}{1})
+	})
+	t.Run("join", func(t *testing.T) {
+		users := newUsersTable(t, "u")
+		posts, _ := NewTable("posts",
+			TableColumn("id", TypeInt(), ColumnPrimaryKey()),
+			TableColumn("user", TypeInt(), ColumnNotNull(), ColumnReferences("users", "id")),
+			TableColumn("title", TypeText()),
+		)
+		posts = posts.As("p")
+		q, err := users.Select(SelectColumn(users.Email))
+		if err != nil {
+			t.Fatalf("error creating query! %s", err)
+		}
+		q, err = q.LeftInnerJoin(posts, Equal(users.ID, posts.Column("user")), SelectColumn(posts.Column("title")))
+		if err != nil {
+			t.Fatalf("error joining table! %s", err)
+		}
+		compareQueries(t, q, "SELECT u.email, p.title FROM users AS u INNER JOIN posts AS p ON u.id = p.user", nil)
+		compareDialect(t, q, Postgres, `SELECT "u"."email", "p"."title" FROM "users" AS "u" INNER JOIN "posts" AS "p" ON "u"."id" = "p"."user"`, nil)
+	})
+	t.Run("columns", func(t *testing.T) {
+		users := newUsersTable(t, "")
+		if users.ID.Nullable() || !users.Email.Nullable() {
+			t.Errorf("nullability of columns mismatched")
+		}
+		if _, ok := users.Email.Type().(columnType); !ok {
+			t.Errorf("expected column type, got %T", users.Email.Type())
+		}
+		if cs := users.Columns(); len(cs) != 2 || cs[0].Name() != "id" || cs[1].Name() != "email" {
+			t.Errorf("columns of table mismatched")
+		}
+		q, _ := users.Select(SelectColumn(users.Column("name")))
+		if _, _, err := q.SQL(); err == nil {
+			t.Errorf("expected error when using unknown column")
+		}
+		q, _ = users.As("0u").Select()
+		if _, _, err := q.SQL(); err == nil {
+			t.Errorf("expected error when using invalid alias")
+		}
+	})
+	t.Run("order", func(t *testing.T) {
+		users := newUsersTable(t, "u")
+		q, err := users.Select(SelectColumn(users.Email), SelectOrderBy(DescExpr(users.ID), AscExpr(users.Email)))
+		if err != nil {
+			t.Fatalf("error creating query! %s", err)
+		}
+		compareQueries(t, q, "SELECT u.email FROM users AS u ORDER BY u.id DESC, u.email ASC", nil)
+	})
+	t.Run("insert", func(t *testing.T) {
+		users := newUsersTable(t, "")
+		q, err := NewInsert(users.Name(), InsertColumn(users.ID), InsertColumn(users.Email), InsertValues(Arg("id", 1), Arg("email", "foo@bar.org")))
+		if err != nil {
+			t.Fatalf("error creating query! %s", err)
+		}
+		compareQueries(t, q, "INSERT INTO users(id, email) VALUES (?, ?)", []interface{}{1, "foo@bar.org"})
+		if _, err := NewInsert(users.Name(), InsertColumn(users.Column("name"))); err == nil {
+			t.Errorf("expected error when inserting unknown column")
+		}
+		if _, err := NewInsert(users.Name(), InsertColumn(Arg("id", 1))); err == nil {
+			t.Errorf("expected error when inserting non column")
+		}
+	})
+	t.Run("update", func(t *testing.T) {
+		users := newUsersTable(t, "")
+		q, err := NewUpdate(users.Name(), UpdateSet(users.Email, Arg("email", "foo@bar.org")), UpdateWhere(Equal(users.ID, Arg("id", 1))))
+		if err != nil {
+			t.Fatalf("error creating query! %s", err)
+		}
+		compareQueries(t, q, "UPDATE users SET email = ? WHERE users.id = ?", []interface{}{"foo@bar.org", 1})
+		if _, err := NewUpdate(users.Name(), UpdateSet(users.Column("name"), Arg("name", "foo"))); err == nil {
+			t.Errorf("expected error when updating unknown column")
+		}
+	})
+	t.Run("create", func(t *testing.T) {
+		users := newUsersTable(t, "u")
+		q, err := users.Create(CreateIfNotExists())
+		if err != nil {
+			t.Fatalf("error creating query! %s", err)
+		}
+		compareQueries(t, q, "CREATE TABLE IF NOT EXISTS users (id INTEGER PRIMARY KEY, email VARCHAR(255))", nil)
+	})
+	if _, err := NewTable("users", TableColumn("id", TypeInt()), TableColumn("id", TypeText())); err == nil {
+		t.Errorf("expected error when defining column twice")
+	}
+}
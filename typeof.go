@@ -0,0 +1,418 @@
+package quel
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// TypeOf returns the type of the value of expr. The columns used by expr are
+// looked up in the tables of s: they should be qualified by the name of their
+// table unless their name is used by only one table of s.
+//
+// The type is inferred through the arithmetic operations, the functions whose
+// signature is known, the CASE expressions, the comparisons and the intervals.
+// TypeOf returns an error wrapping ErrType if the type can not be inferred,
+// or the error of the first column that can not be found.
+func TypeOf(expr SQLer, s Schema) (SQLer, error) {
+	var (
+		v  = validator{schema: s}
+		sc scope
+	)
+	for _, t := range s.tables {
+		sc.sources = append(sc.sources, tableRelation(t))
+	}
+	v.checkExpr(expr, "", &sc, false)
+	if len(v.diags) > 0 {
+		return nil, v.diags[0]
+	}
+	c := v.infer(expr, &sc)
+	if c.kind == nil {
+		return nil, fmt.Errorf("type: %w: can not be inferred", ErrType)
+	}
+	return c.kind, nil
+}
+
+// ResultColumn describes a column of the result of a query.
+type ResultColumn struct {
+	// Name is the name of the column or its alias. It is empty for the
+	// expressions that are not aliased.
+	Name string
+	// Type is nil if the type of the column can not be inferred.
+	Type     SQLer
+	Nullable bool
+}
+
+// ResultColumns returns the columns of the result of s with the tables and
+// the columns defined by schema. The columns selected with * are expanded.
+// The error returned is the first diagnostic reported by Validate.
+func (s Select) ResultColumns(schema Schema) ([]ResultColumn, error) {
+	v := validator{schema: schema}
+	columns := v.validate(s, "", nil)
+	if len(v.diags) > 0 {
+		return nil, v.diags[0]
+	}
+	if columns == nil {
+		return nil, fmt.Errorf("select: %w: columns of result can not be known", ErrColumn)
+	}
+	list := make([]ResultColumn, len(columns))
+	for i, c := range columns {
+		list[i] = ResultColumn{
+			Name:     c.name,
+			Type:     c.kind,
+			Nullable: c.nullable,
+		}
+	}
+	return list, nil
+}
+
+// signature gives the type and the nullability of the result of a function
+// from the ones of its arguments.
+type signature func(args []relColumn) relColumn
+
+var signatures = map[string]signature{
+	"COUNT":        returns(TypeBigInt(), false),
+	"SUM":          sumOf,
+	"AVG":          avgOf,
+	"MIN":          sameAs(true),
+	"MAX":          sameAs(true),
+	"COALESCE":     coalesceOf,
+	"IFNULL":       coalesceOf,
+	"NULLIF":       sameAs(true),
+	"ISNULL":       returns(TypeBool(), false),
+	"NOW":          returns(TypeTimestamp(), false),
+	"DATE":         returns(TypeDate(), true),
+	"ROW_NUMBER":   returns(TypeBigInt(), false),
+	"RANK":         returns(TypeBigInt(), false),
+	"DENSE_RANK":   returns(TypeBigInt(), false),
+	"NTILE":        returns(TypeBigInt(), false),
+	"LAG":          sameAs(true),
+	"LEAD":         sameAs(true),
+	"FIRST_VALUE":  sameAs(false),
+	"LAST_VALUE":   sameAs(false),
+	"ABS":          sameAs(false),
+	"ROUND":        sameAs(false),
+	"CEIL":         sameAs(false),
+	"FLOOR":        sameAs(false),
+	"LOWER":        returns(TypeText(), true),
+	"UPPER":        returns(TypeText(), true),
+	"TRIM":         returns(TypeText(), true),
+	"SUBSTR":       returns(TypeText(), true),
+	"SUBSTRING":    returns(TypeText(), true),
+	"REPLACE":      returns(TypeText(), true),
+	"CONCAT":       returns(TypeText(), true),
+	"LENGTH":       returns(TypeInt(), true),
+	"CHAR_LENGTH":  returns(TypeInt(), true),
+	"OCTET_LENGTH": returns(TypeInt(), true),
+}
+
+// returns gives a signature returning kind. The result is nullable if
+// nullable is true and one of the arguments is nullable.
+func returns(kind SQLer, nullable bool) signature {
+	return func(args []relColumn) relColumn {
+		return relColumn{
+			kind:     kind,
+			nullable: nullable && anyNullable(args),
+		}
+	}
+}
+
+// sameAs gives a signature returning the type of the first argument. The
+// result is always nullable if null is true.
+func sameAs(null bool) signature {
+	return func(args []relColumn) relColumn {
+		if len(args) == 0 {
+			return relColumn{nullable: true}
+		}
+		c := args[0]
+		c.nullable = c.nullable || null
+		return c
+	}
+}
+
+func sumOf(args []relColumn) relColumn {
+	c := relColumn{nullable: true}
+	if len(args) == 0 {
+		return c
+	}
+	if t, ok := args[0].kind.(columnType); ok {
+		switch t.kind {
+		case typeSmallInt, typeInt, typeBigInt:
+			c.kind = TypeBigInt()
+		case typeDecimal:
+			c.kind = t
+		case typeReal, typeDouble:
+			c.kind = TypeDouble()
+		case typeInterval:
+			c.kind = t
+		}
+	}
+	return c
+}
+
+func avgOf(args []relColumn) relColumn {
+	c := relColumn{nullable: true}
+	if len(args) == 0 {
+		return c
+	}
+	if t, ok := args[0].kind.(columnType); ok {
+		switch t.kind {
+		case typeDecimal, typeInterval:
+			c.kind = t
+		default:
+			if typeFamily(t) == "numeric" {
+				c.kind = TypeDouble()
+			}
+		}
+	}
+	return c
+}
+
+// coalesceOf returns the common type of the arguments. The result is nullable
+// only if all the arguments are nullable.
+func coalesceOf(args []relColumn) relColumn {
+	c := relColumn{nullable: true}
+	for _, a := range args {
+		c.kind = commonType(c.kind, a.kind)
+		c.nullable = c.nullable && a.nullable
+	}
+	return c
+}
+
+func anyNullable(args []relColumn) bool {
+	for _, a := range args {
+		if a.nullable {
+			return true
+		}
+	}
+	return false
+}
+
+// infer returns the type and the nullability of expr whose columns are
+// resolved in sc. The type is nil if it can not be inferred.
+func (v *validator) infer(expr SQLer, sc *scope) relColumn {
+	switch e := expr.(type) {
+	case alias:
+		return v.infer(e.SQLer, sc)
+	case ident:
+		if kind := pseudoType(e.name); kind != nil && len(e.parents) == 0 {
+			return relColumn{kind: kind}
+		}
+		c, err := v.resolve(e.name, e.parents, sc, true)
+		if err != nil {
+			return relColumn{nullable: true}
+		}
+		return c
+	case Column:
+		if e.kind != nil {
+			return relColumn{kind: e.kind, nullable: e.nullable}
+		}
+		c, err := v.resolve(e.name, e.Parents(), sc, true)
+		if err != nil {
+			return relColumn{nullable: true}
+		}
+		return c
+	case literal:
+		return relColumn{kind: literalType(e.value), nullable: e.value == nil}
+	case arg:
+		return relColumn{kind: literalType(e.value), nullable: e.value == nil}
+	case valueArg:
+		return relColumn{kind: literalType(e.value), nullable: e.value == nil}
+	case placeholder:
+		return relColumn{nullable: true}
+	case duration:
+		return relColumn{kind: TypeInterval()}
+	case arithmetic:
+		var (
+			left  = v.infer(e.left, sc)
+			right = v.infer(e.right, sc)
+		)
+		return relColumn{
+			kind:     arithmeticType(e.op, left.kind, right.kind),
+			nullable: left.nullable || right.nullable,
+		}
+	case concat:
+		var (
+			left  = v.infer(e.left, sc)
+			right = v.infer(e.right, sc)
+		)
+		return relColumn{kind: TypeText(), nullable: left.nullable || right.nullable}
+	case cast:
+		return relColumn{kind: e.kind, nullable: v.infer(e.expr, sc).nullable}
+	case function:
+		args := make([]relColumn, len(e.args))
+		for i := range e.args {
+			args[i] = v.infer(e.args[i], sc)
+		}
+		if fn, ok := signatures[strings.ToUpper(e.name)]; ok {
+			return fn(args)
+		}
+		return relColumn{nullable: true}
+	case window:
+		return v.infer(e.fn, sc)
+	case kase:
+		var c relColumn
+		for _, x := range e.csq {
+			x := v.infer(x, sc)
+			c.kind = commonType(c.kind, x.kind)
+			c.nullable = c.nullable || x.nullable
+		}
+		if e.alt == nil {
+			c.nullable = true
+		} else {
+			x := v.infer(e.alt, sc)
+			c.kind = commonType(c.kind, x.kind)
+			c.nullable = c.nullable || x.nullable
+		}
+		return c
+	case compare:
+		c := relColumn{kind: TypeBool()}
+		if e.op == isnull || e.op == isnotnull {
+			return c
+		}
+		c.nullable = v.infer(e.left, sc).nullable || v.infer(e.right, sc).nullable
+		return c
+	case and:
+		return v.logical(sc, e.left, e.right)
+	case or:
+		return v.logical(sc, e.left, e.right)
+	case not:
+		return v.logical(sc, e.right)
+	case negate:
+		return v.infer(e.expr, sc)
+	case distinct:
+		return v.infer(e.expr, sc)
+	case between:
+		return v.logical(sc, e.value, e.left, e.right)
+	case exist:
+		return relColumn{kind: TypeBool()}
+	case Select, union:
+		// a scalar subquery gives NULL when it returns no rows
+		w := validator{schema: v.schema}
+		columns := w.validate(e, "", sc)
+		if len(columns) == 0 {
+			return relColumn{nullable: true}
+		}
+		return relColumn{kind: columns[0].kind, nullable: true}
+	default:
+		return relColumn{nullable: true}
+	}
+}
+
+// logical returns the type of a boolean expression using list. It is
+// nullable if one of the elements of list is nullable.
+func (v *validator) logical(sc *scope, list ...SQLer) relColumn {
+	c := relColumn{kind: TypeBool()}
+	for _, x := range list {
+		c.nullable = c.nullable || v.infer(x, sc).nullable
+	}
+	return c
+}
+
+func pseudoType(name string) SQLer {
+	switch strings.ToUpper(name) {
+	case "CURRENT_DATE":
+		return TypeDate()
+	case "CURRENT_TIME", "LOCALTIME":
+		return TypeTime()
+	case "CURRENT_TIMESTAMP", "LOCALTIMESTAMP":
+		return TypeTimestamp()
+	case "CURRENT_USER":
+		return TypeText()
+	default:
+		return nil
+	}
+}
+
+func literalType(value interface{}) SQLer {
+	switch value.(type) {
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+		return TypeBigInt()
+	case float32, float64:
+		return TypeDouble()
+	case string:
+		return TypeText()
+	case bool:
+		return TypeBool()
+	case time.Time:
+		return TypeTimestamp()
+	case time.Duration:
+		return TypeInterval()
+	case []byte:
+		return TypeBlob()
+	default:
+		return nil
+	}
+}
+
+// arithmeticType returns the type of the result of an arithmetic operation.
+// The operations on numbers give the widest type of their operands. Adding an
+// interval to a date or a timestamp gives the type of the date and the
+// difference between two dates gives a number of days or an interval.
+func arithmeticType(op uint8, left, right SQLer) SQLer {
+	lt, ok := left.(columnType)
+	if !ok {
+		return nil
+	}
+	rt, ok := right.(columnType)
+	if !ok {
+		return nil
+	}
+	var (
+		lf = typeFamily(lt)
+		rf = typeFamily(rt)
+	)
+	switch {
+	case lf == "numeric" && rf == "numeric":
+		return commonType(lt, rt)
+	case lf == "date" && (rf == "interval" || rf == "numeric") && (op == add || op == sub):
+		return lt
+	case lf == "interval" && rf == "date" && op == add:
+		return rt
+	case lf == "date" && rf == "date" && op == sub:
+		if lt.kind == typeDate && rt.kind == typeDate {
+			return TypeInt()
+		}
+		return TypeInterval()
+	case lf == "time" && rf == "interval" && (op == add || op == sub):
+		return lt
+	case lf == "interval" && rf == "interval" && (op == add || op == sub):
+		return lt
+	case lf == "interval" && rf == "numeric" && (op == mul || op == div):
+		return lt
+	case lf == "numeric" && rf == "interval" && op == mul:
+		return rt
+	default:
+		return nil
+	}
+}
+
+var numericRanks = map[uint8]int{
+	typeSmallInt: 1,
+	typeInt:      2,
+	typeBigInt:   3,
+	typeDecimal:  4,
+	typeReal:     5,
+	typeDouble:   6,
+}
+
+// commonType returns the type that can hold the values of left and right.
+// For numbers, it is the widest of both types. Otherwise, it is the first
+// known type.
+func commonType(left, right SQLer) SQLer {
+	if left == nil {
+		return right
+	}
+	lt, ok := left.(columnType)
+	if !ok {
+		return left
+	}
+	rt, ok := right.(columnType)
+	if !ok {
+		return left
+	}
+	if numericRanks[rt.kind] > numericRanks[lt.kind] && numericRanks[lt.kind] > 0 {
+		return rt
+	}
+	return left
+}
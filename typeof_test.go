@@ -0,0 +1,138 @@
+package quel
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestTypeOf(t *testing.T) {
+	schema, err := LoadSchema(testSchema, Default)
+	if err != nil {
+		t.Fatalf("error loading schema! %s", err)
+	}
+	data := []struct {
+		Expr SQLer
+		Want string
+		Err  error
+	}{
+		{Expr: NewIdent("name", "users"), Want: "quel.TypeVarchar(64)"},
+		{Expr: NewIdent("title"), Want: "quel.TypeText()"},
+		{Expr: NewIdent("id"), Err: ErrColumn},
+		{Expr: NewIdent("id", "comments"), Err: ErrIdent},
+		{Expr: Add(NewIdent("id", "users"), NewLiteral(1.5)), Want: "quel.TypeDouble()"},
+		{Expr: Multiply(NewIdent("id", "users"), NewIdent("user", "posts")), Want: "quel.TypeInt()"},
+		{Expr: Count(NewIdent("id", "posts")), Want: "quel.TypeBigInt()"},
+		{Expr: Sum(NewIdent("user")), Want: "quel.TypeBigInt()"},
+		{Expr: Max(NewIdent("email")), Want: "quel.TypeVarchar(255)"},
+		{Expr: Coalesce(NewIdent("body"), NewLiteral("")), Want: "quel.TypeText()"},
+		{Expr: Date(NewIdent("created")), Want: "quel.TypeDate()"},
+		{Expr: Add(NewIdent("created"), Days(1)), Want: "quel.TypeTimestamp()"},
+		{Expr: Subtract(NewIdent("created"), NewIdent("CURRENT_TIMESTAMP")), Want: "quel.TypeInterval()"},
+		{Expr: Hours(2), Want: "quel.TypeInterval()"},
+		{Expr: GreaterThan(NewIdent("created"), Now()), Want: "quel.TypeBool()"},
+		{Expr: And(IsNullTest(NewIdent("body")), Equal(NewIdent("title"), Arg("title", ""))), Want: "quel.TypeBool()"},
+		{Expr: Over(RowNumber()), Want: "quel.TypeBigInt()"},
+		{Expr: Func("SOUNDEX", NewIdent("title")), Err: ErrType},
+		{Expr: Add(NewIdent("title"), NewLiteral(1)), Err: ErrType},
+	}
+	for _, d := range data {
+		sql, _, _ := d.Expr.SQL()
+		got, err := TypeOf(d.Expr, schema)
+		if d.Err != nil {
+			if !errors.Is(err, d.Err) {
+				t.Errorf("%s: expected error %s, got %v", sql, d.Err, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: unexpected error! %s", sql, err)
+			continue
+		}
+		if str := fmt.Sprintf("%#v", got); str != d.Want {
+			t.Errorf("%s: type mismatched! want %s, got %s", sql, d.Want, str)
+		}
+	}
+}
+
+func TestResultColumns(t *testing.T) {
+	schema, err := LoadSchema(testSchema, Default)
+	if err != nil {
+		t.Fatalf("error loading schema! %s", err)
+	}
+	data := []struct {
+		Query string
+		Want  []ResultColumn
+	}{
+		{
+			Query: "SELECT * FROM users",
+			Want: []ResultColumn{
+				{Name: "id", Type: TypeInt()},
+				{Name: "name", Type: TypeVarchar(64)},
+				{Name: "email", Type: TypeVarchar(255)},
+				{Name: "created", Type: TypeTimestamp()},
+			},
+		},
+		{
+			Query: "SELECT u.name, p.title, p.body, COUNT(p.id) AS total FROM users u LEFT OUTER JOIN posts p ON u.id = p.user GROUP BY u.name, p.title, p.body",
+			Want: []ResultColumn{
+				{Name: "name", Type: TypeVarchar(64)},
+				{Name: "title", Type: TypeText(), Nullable: true},
+				{Name: "body", Type: TypeText(), Nullable: true},
+				{Name: "total", Type: TypeBigInt()},
+			},
+		},
+		{
+			Query: "SELECT id, CASE WHEN body IS NULL THEN 0 ELSE LENGTH(body) END AS size, created + INTERVAL 1 DAY AS expire, (SELECT MAX(id) FROM posts) AS last FROM posts JOIN users USING (id)",
+			Want: []ResultColumn{
+				{Name: "id", Type: TypeInt()},
+				{Name: "size", Type: TypeBigInt(), Nullable: true},
+				{Name: "expire", Type: TypeTimestamp()},
+				{Name: "last", Type: TypeInt(), Nullable: true},
+			},
+		},
+		{
+			Query: "WITH recent AS (SELECT id, title FROM posts WHERE id > 10) SELECT r.*, title = 'quel' AS match, SOUNDEX(title) FROM recent r",
+			Want: []ResultColumn{
+				{Name: "id", Type: TypeInt()},
+				{Name: "title", Type: TypeText()},
+				{Name: "match", Type: TypeBool()},
+				{Nullable: true},
+			},
+		},
+	}
+	for _, d := range data {
+		q, err := Parse(d.Query, Default)
+		if err != nil {
+			t.Errorf("%s: error parsing query! %s", d.Query, err)
+			continue
+		}
+		s, ok := q.(Select)
+		if !ok {
+			t.Errorf("%s: expected select, got %T", d.Query, q)
+			continue
+		}
+		got, err := s.ResultColumns(schema)
+		if err != nil {
+			t.Errorf("%s: unexpected error! %s", d.Query, err)
+			continue
+		}
+		if len(got) != len(d.Want) {
+			t.Errorf("%s: columns mismatched! want %d, got %d", d.Query, len(d.Want), len(got))
+			continue
+		}
+		for i := range got {
+			if got[i].Name != d.Want[i].Name || got[i].Nullable != d.Want[i].Nullable {
+				t.Errorf("%s: column %d mismatched! want %+v, got %+v", d.Query, i, d.Want[i], got[i])
+			}
+			if want, got := fmt.Sprintf("%#v", d.Want[i].Type), fmt.Sprintf("%#v", got[i].Type); want != got {
+				t.Errorf("%s: type of column %d mismatched! want %s, got %s", d.Query, i, want, got)
+			}
+		}
+	}
+
+	s, _ := NewSelect("comments")
+	if _, err := s.ResultColumns(schema); !errors.Is(err, ErrIdent) {
+		t.Errorf("expected error when selecting from unknown table, got %v", err)
+	}
+}
@@ -17,6 +17,55 @@ func UpdateColumn(ident string, value SQLer) UpdateOption {
 	}
 }
 
+// UpdateSet is like UpdateColumn but column is an identifier or a Column of a
+// Table. Its qualifier is not written.
+func UpdateSet(column, value SQLer) UpdateOption {
+	return func(u *Update) error {
+		name, err := columnName(column)
+		if err == nil {
+			u.columns = append(u.columns, Equal(NewIdent(name), value))
+		}
+		return err
+	}
+}
+
+// UpdateStruct adds a column to update for each field of v, a struct or a
+// pointer to a struct. The names of the columns are given by the db tag of the
+// fields (see ScanAll) and their values are given as arguments named after
+// their column. These names do not collide with the ones of the other
+// arguments of the query (see InsertStruct).
+func UpdateStruct(v interface{}, options ...StructOption) UpdateOption {
+	return func(u *Update) error {
+		columns, rows, err := structRows(v, options)
+		if err != nil {
+			return err
+		}
+		if len(rows) != 1 {
+			return fmt.Errorf("update: %w: only one row can be updated", ErrArg)
+		}
+		for j, c := range columns {
+			u.columns = append(u.columns, Equal(NewIdent(c), argValue(c, rows[0][j])))
+		}
+		return nil
+	}
+}
+
+// UpdateMap adds a column to update for each key of m. The columns are sorted
+// by name. Values that are not SQLer are given as arguments named like the
+// ones of UpdateStruct.
+func UpdateMap(m map[string]interface{}) UpdateOption {
+	return func(u *Update) error {
+		columns, values, err := mapRow(m)
+		if err != nil {
+			return err
+		}
+		for j, c := range columns {
+			u.columns = append(u.columns, Equal(NewIdent(c), argValue(c, values[j])))
+		}
+		return nil
+	}
+}
+
 func UpdateWhere(where SQLer) UpdateOption {
 	return func(u *Update) error {
 		if where == nil {
@@ -30,6 +79,48 @@ func UpdateWhere(where SQLer) UpdateOption {
 	}
 }
 
+// UpdateFrom adds a table whose columns can be used in the SET and WHERE
+// clauses. It is rendered as FROM except for MySQL where the tables are
+// listed after the updated one. A subquery needs an alias to be given as
+// source.
+func UpdateFrom(source SQLer) UpdateOption {
+	return func(u *Update) error {
+		if !isSource(source) {
+			return fmt.Errorf("from: %w: source can not be joined", ErrSyntax)
+		}
+		u.sources = append(u.sources, query{table: source})
+		return nil
+	}
+}
+
+// UpdateJoin adds a table joined to the updated one with cdt. It is rendered
+// as a JOIN for MySQL. The other dialects render it as UpdateFrom with cdt
+// added to the WHERE clause.
+func UpdateJoin(source, cdt SQLer) UpdateOption {
+	return func(u *Update) error {
+		if !isSource(source) {
+			return fmt.Errorf("join: %w: source can not be joined", ErrSyntax)
+		}
+		if !acceptRelational(cdt) {
+			return fmt.Errorf("join: %w: invalid condition type", ErrSyntax)
+		}
+		u.sources = append(u.sources, query{table: source, cdt: cdt, join: innerLeft})
+		return nil
+	}
+}
+
+// UpdateWith adds a common table expression to the statement. See
+// SelectWith.
+func UpdateWith(name string, query SQLer, columns ...SQLer) UpdateOption {
+	return func(u *Update) error {
+		c, err := newCte(name, query, columns)
+		if err == nil {
+			u.ctes = append(u.ctes, c)
+		}
+		return err
+	}
+}
+
 func UpdateAlias(alias string) UpdateOption {
 	return func(u *Update) error {
 		if !isValidIdentifier(alias) {
@@ -48,7 +139,9 @@ func UpdateReturn(values ...SQLer) UpdateOption {
 }
 
 type Update struct {
+	ctes      []SQLer
 	table     SQLer
+	sources   []query
 	columns   []SQLer
 	where     SQLer
 	returning []SQLer
@@ -65,32 +158,82 @@ func NewUpdate(table string, options ...UpdateOption) (Update, error) {
 			break
 		}
 	}
-	if len(u.columns) == 0 {
-		return u, fmt.Errorf("%w: no columns to update", ErrSyntax)
+	if err == nil && len(u.columns) == 0 {
+		err = fmt.Errorf("%w: no columns to update", ErrSyntax)
 	}
 	return u, err
 }
 
+// Apply returns a copy of u with options applied to it. u is left unmodified.
+func (u Update) Apply(options ...UpdateOption) (Update, error) {
+	base := u
+	base.ctes = append([]SQLer{}, u.ctes...)
+	base.sources = append([]query{}, u.sources...)
+	base.columns = append([]SQLer{}, u.columns...)
+	base.returning = append([]SQLer{}, u.returning...)
+	for _, opt := range options {
+		if err := opt(&base); err != nil {
+			return u, err
+		}
+	}
+	return base, nil
+}
+
 func (u Update) SQL() (string, []interface{}, error) {
-	var (
-		b    strings.Builder
-		args []interface{}
-	)
-	b.WriteString("UPDATE ")
-	sql, _, err := u.table.SQL()
+	return SQLDialect(u, Default)
+}
+
+func (u Update) SQLDialect(d Dialect) (string, []interface{}, error) {
+	return SQLDialect(u, d)
+}
+
+func (u Update) render(r *renderer) (string, []interface{}, error) {
+	var b strings.Builder
+	args, err := writeCtes(r, &b, u.ctes)
 	if err != nil {
 		return "", nil, err
 	}
-	b.WriteString(sql)
-	b.WriteString(" SET ")
-	as, err := writeSQL(&b, u.columns...)
+	where := u.where
+	if r.is(MySQL) {
+		as, err := writeSources(r, &b, "UPDATE", append([]query{{table: u.table}}, u.sources...))
+		if err != nil {
+			return "", nil, err
+		}
+		args = append(args, as...)
+	} else {
+		sql, _, err := r.render(u.table)
+		if err != nil {
+			return "", nil, err
+		}
+		b.WriteString(r.keyword("UPDATE") + " ")
+		b.WriteString(sql)
+	}
+	b.WriteString(r.clause("SET"))
+	as, err := writeSQL(r, &b, u.columns...)
 	if err != nil {
 		return "", nil, err
 	}
 	args = append(args, as...)
-	if u.where != nil {
-		b.WriteString(" WHERE ")
-		sql, as, err := u.where.SQL()
+	if len(u.sources) > 0 && !r.is(MySQL) {
+		var (
+			from  = make([]query, len(u.sources))
+			preds []SQLer
+		)
+		for i, q := range u.sources {
+			from[i] = query{table: q.table}
+			preds = append(preds, q.cdt)
+		}
+		b.WriteString(r.sep())
+		as, err := writeSources(r, &b, "FROM", from)
+		if err != nil {
+			return "", nil, err
+		}
+		args = append(args, as...)
+		where = conjunction(append(preds, where)...)
+	}
+	if where != nil {
+		b.WriteString(r.clause("WHERE"))
+		sql, as, err := r.render(where)
 		if err != nil {
 			return "", nil, err
 		}
@@ -98,8 +241,8 @@ func (u Update) SQL() (string, []interface{}, error) {
 		b.WriteString(sql)
 	}
 	if u.returning != nil {
-		b.WriteString(" RETURNING ")
-		as, err := writeSQL(&b, u.returning...)
+		b.WriteString(r.clause("RETURNING"))
+		as, err := writeSQL(r, &b, u.returning...)
 		if err != nil {
 			return "", nil, err
 		}
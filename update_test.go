@@ -1,9 +1,119 @@
 package quel
 
 import (
+	"database/sql"
+	"errors"
+	"reflect"
 	"testing"
 )
 
+func TestUpdateStruct(t *testing.T) {
+	type user struct {
+		ID   int    `db:"id,pk"`
+		Name string `db:"name"`
+		Role string `db:"role"`
+	}
+	data := []struct {
+		Options []UpdateOption
+		Want    string
+		Args    []interface{}
+	}{
+		{
+			Options: []UpdateOption{
+				UpdateStruct(user{ID: 1, Name: "roger"}, SkipKeys(), SkipZero()),
+				UpdateWhere(Equal(NewIdent("id"), Arg("id", 1))),
+			},
+			Want: "UPDATE users SET name = ? WHERE id = ?",
+			Args: []interface{}{"roger", 1},
+		},
+		{
+			Options: []UpdateOption{
+				UpdateMap(map[string]interface{}{"role": "admin", "name": "roger"}),
+			},
+			Want: "UPDATE users SET name = ?, role = ?",
+			Args: []interface{}{"roger", "admin"},
+		},
+	}
+	for _, d := range data {
+		q, err := NewUpdate("users", d.Options...)
+		if err != nil {
+			t.Errorf("error creating query! %s", err)
+			continue
+		}
+		compareQueries(t, q, d.Want, d.Args)
+	}
+
+	q, err := NewUpdate("users", UpdateStruct(user{ID: 1, Name: "roger"}, SkipKeys(), SkipZero()), UpdateWhere(Equal(NewIdent("name"), Arg("name", "old"))))
+	if err != nil {
+		t.Fatalf("error creating query! %s", err)
+	}
+	compareDialect(t, q, Postgres, `UPDATE "users" SET "name" = $1 WHERE "name" = $2`, []interface{}{"roger", "old"})
+	compareDialect(t, q, SQLite, `UPDATE "users" SET "name" = ? WHERE "name" = ?`, []interface{}{"roger", "old"})
+	str, args, err := SQLNamed(q, SQLite)
+	if err != nil {
+		t.Fatalf("error building query! %s", err)
+	}
+	if want := `UPDATE "users" SET "name" = :name_1 WHERE "name" = :name`; str != want {
+		t.Errorf("queries mismatched!")
+		t.Logf("\twant: %s", want)
+		t.Logf("\tgot:  %s", str)
+	}
+	named := []sql.NamedArg{sql.Named("name_1", "roger"), sql.Named("name", "old")}
+	if !reflect.DeepEqual(args, named) {
+		t.Errorf("arguments mismatched! want %v, got %v", named, args)
+	}
+	tpl, err := Compile(q, Postgres)
+	if err != nil {
+		t.Fatalf("error compiling query! %s", err)
+	}
+	if want := `UPDATE "users" SET "name" = $1 WHERE "name" = $2`; tpl.Query() != want {
+		t.Errorf("queries mismatched!")
+		t.Logf("\twant: %s", want)
+		t.Logf("\tgot:  %s", tpl.Query())
+	}
+	if want := []string{"name_1", "name"}; !reflect.DeepEqual(tpl.Params(), want) {
+		t.Errorf("parameters mismatched! want %v, got %v", want, tpl.Params())
+	}
+
+	if _, err := NewUpdate("users", UpdateStruct(nil)); !errors.Is(err, ErrArg) {
+		t.Errorf("expected ErrArg when giving a nil value, got %v", err)
+	}
+
+	q, err = NewUpdate("users", UpdateStruct(user{ID: 1, Name: "roger", Role: "admin"}, SkipKeys()), UpdateWhere(Equal(NewIdent("id"), Arg("id", 1))))
+	if err != nil {
+		t.Fatalf("error creating query! %s", err)
+	}
+	str, args, err = SQLNamed(q, SQLite)
+	if err != nil {
+		t.Fatalf("error building query! %s", err)
+	}
+	if want := `UPDATE "users" SET "name" = :name, "role" = :role WHERE "id" = :id`; str != want {
+		t.Errorf("queries mismatched!")
+		t.Logf("\twant: %s", want)
+		t.Logf("\tgot:  %s", str)
+	}
+	named = []sql.NamedArg{sql.Named("name", "roger"), sql.Named("role", "admin"), sql.Named("id", 1)}
+	if !reflect.DeepEqual(args, named) {
+		t.Errorf("arguments mismatched! want %v, got %v", named, args)
+	}
+	tpl, err = Compile(q, Postgres)
+	if err != nil {
+		t.Fatalf("error compiling query! %s", err)
+	}
+	if want := `UPDATE "users" SET "name" = $1, "role" = $2 WHERE "id" = $3`; tpl.Query() != want {
+		t.Errorf("queries mismatched!")
+		t.Logf("\twant: %s", want)
+		t.Logf("\tgot:  %s", tpl.Query())
+	}
+	values, err := tpl.BindStruct(user{ID: 2, Name: "pierre", Role: "test"})
+	if err != nil {
+		t.Fatalf("error binding struct! %s", err)
+	}
+	if want := []interface{}{"pierre", "test", 2}; !reflect.DeepEqual(values, want) {
+		t.Errorf("arguments mismatched! want %v, got %v", want, values)
+	}
+}
+
 func TestUpdate(t *testing.T) {
 	data := []struct {
 		Options []UpdateOption
@@ -28,6 +138,14 @@ func TestUpdate(t *testing.T) {
 			Want:  "UPDATE users SET role = 'test', active = 1 WHERE active = ?",
 			Args:  []interface{}{0},
 		},
+		{
+			Options: []UpdateOption{
+				UpdateColumn("active", NewLiteral(0)),
+				UpdateWhere(Not(Equal(NewIdent("role"), NewLiteral("admin")))),
+			},
+			Table: "users",
+			Want:  "UPDATE users SET active = 0 WHERE NOT role = 'admin'",
+		},
 	}
 	for _, d := range data {
 		q, err := NewUpdate(d.Table, d.Options...)
@@ -38,3 +156,84 @@ func TestUpdate(t *testing.T) {
 		compareQueries(t, q, d.Want, d.Args)
 	}
 }
+
+func TestUpdateFrom(t *testing.T) {
+	staging, err := NewSelect("staging", SelectColumns("id", "role"))
+	if err != nil {
+		t.Fatalf("error creating query! %s", err)
+	}
+	data := []struct {
+		Options []UpdateOption
+		Dialect Dialect
+		Want    string
+		Args    []interface{}
+	}{
+		{
+			Options: []UpdateOption{
+				UpdateAlias("u"),
+				UpdateColumn("role", NewIdent("role", "r")),
+				UpdateFrom(Alias("r", NewIdent("roles"))),
+				UpdateWhere(Equal(NewIdent("id", "r"), NewIdent("user", "u"))),
+			},
+			Dialect: Postgres,
+			Want:    `UPDATE "users" AS "u" SET "role" = "r"."role" FROM "roles" AS "r" WHERE "r"."id" = "u"."user"`,
+		},
+		{
+			Options: []UpdateOption{
+				UpdateColumn("role", NewIdent("role", "s")),
+				UpdateFrom(Alias("s", staging)),
+				UpdateWhere(Equal(NewIdent("id", "s"), NewIdent("id", "users"))),
+			},
+			Dialect: Postgres,
+			Want:    `UPDATE "users" SET "role" = "s"."role" FROM (SELECT "id", "role" FROM "staging") AS "s" WHERE "s"."id" = "users"."id"`,
+		},
+		{
+			Options: []UpdateOption{
+				UpdateColumn("active", Arg("active", false)),
+				UpdateJoin(NewIdent("bans"), Equal(NewIdent("user", "bans"), NewIdent("id", "users"))),
+				UpdateWhere(LesserThan(NewIdent("until", "bans"), Now())),
+			},
+			Dialect: Postgres,
+			Want:    `UPDATE "users" SET "active" = $1 FROM "bans" WHERE "bans"."user" = "users"."id" AND "bans"."until" < NOW()`,
+			Args:    []interface{}{false},
+		},
+		{
+			Options: []UpdateOption{
+				UpdateColumn("active", Arg("active", false)),
+				UpdateJoin(NewIdent("bans"), Equal(NewIdent("user", "bans"), NewIdent("id", "users"))),
+				UpdateWhere(LesserThan(NewIdent("until", "bans"), Now())),
+			},
+			Dialect: MySQL,
+			Want:    "UPDATE `users` INNER JOIN `bans` ON `bans`.`user` = `users`.`id` SET `active` = ? WHERE `bans`.`until` < NOW()",
+			Args:    []interface{}{false},
+		},
+		{
+			Options: []UpdateOption{
+				UpdateColumn("active", Arg("active", false)),
+				UpdateFrom(NewIdent("bans")),
+			},
+			Dialect: MySQL,
+			Want:    "UPDATE `users`, `bans` SET `active` = ?",
+			Args:    []interface{}{false},
+		},
+	}
+	for _, d := range data {
+		q, err := NewUpdate("users", d.Options...)
+		if err != nil {
+			t.Errorf("error creating query! %s", err)
+			continue
+		}
+		compareDialect(t, q, d.Dialect, d.Want, d.Args)
+	}
+	invalid := []UpdateOption{
+		UpdateFrom(NewLiteral(1)),
+		UpdateFrom(staging),
+		UpdateJoin(staging, Equal(NewIdent("id", "staging"), NewIdent("id", "users"))),
+		UpdateJoin(NewIdent("bans"), Using(NewIdent("id"))),
+	}
+	for _, opt := range invalid {
+		if _, err := NewUpdate("users", UpdateColumn("active", Arg("active", false)), opt); err == nil {
+			t.Errorf("expected error with invalid source")
+		}
+	}
+}
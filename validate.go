@@ -0,0 +1,706 @@
+package quel
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Diagnostic is a problem found by Validate. Path locates the clause of the
+// query where the problem was found with the names of the clauses leading to
+// it, eg select.where or union.right.select.columns[1]. Node is the node of
+// the query causing the problem.
+type Diagnostic struct {
+	Path    string
+	Node    SQLer
+	Message string
+	Err     error
+}
+
+func (d Diagnostic) Error() string {
+	if d.Path == "" {
+		return d.Message
+	}
+	return fmt.Sprintf("%s: %s", d.Path, d.Message)
+}
+
+func (d Diagnostic) Unwrap() error {
+	return d.Err
+}
+
+// Validate checks the tables and the columns used by q against the tables of
+// s. q is a Select, a union, an Insert, an Update or a Delete. The statements
+// used as subqueries or defined as common table expressions are checked too.
+//
+// It reports the unknown tables (ErrIdent), the unknown or ambiguous columns
+// (ErrColumn), the columns declared NOT NULL without default value that are
+// not given to an Insert (ErrColumn) and the columns of the branches of a
+// union whose types disagree (ErrType). Validate returns nil if no problem is
+// found.
+func Validate(q SQLer, s Schema) []Diagnostic {
+	v := validator{
+		schema: s,
+	}
+	v.validate(q, "", nil)
+	return v.diags
+}
+
+type validator struct {
+	schema Schema
+	diags  []Diagnostic
+}
+
+// relation is a source of rows: a table of the schema, a common table
+// expression or a subquery. Its columns are only checked when they are known.
+type relation struct {
+	name    string
+	columns []relColumn
+	known   bool
+	table   *Table
+}
+
+type relColumn struct {
+	name     string
+	kind     SQLer
+	nullable bool
+}
+
+func (r relation) column(name string) (relColumn, bool) {
+	name = unquote(name)
+	for _, c := range r.columns {
+		if strings.EqualFold(c.name, name) {
+			return c, true
+		}
+	}
+	return relColumn{}, false
+}
+
+func tableRelation(t Table) relation {
+	r := relation{
+		name:  t.name,
+		known: true,
+		table: &t,
+	}
+	for _, c := range t.columns {
+		r.columns = append(r.columns, relColumn{
+			name:     c.name,
+			kind:     c.kind,
+			nullable: t.newColumn(c).nullable,
+		})
+	}
+	return r
+}
+
+// orNull returns a copy of r whose columns are nullable, for the relations
+// that are on the optional side of an outer join.
+func (r relation) orNull() relation {
+	columns := make([]relColumn, len(r.columns))
+	for i, c := range r.columns {
+		c.nullable = true
+		columns[i] = c
+	}
+	r.columns = columns
+	return r
+}
+
+// scope holds the relations that can be referred to by a statement. The
+// relations of the enclosing statements are available through parent.
+type scope struct {
+	parent  *scope
+	ctes    []relation
+	sources []relation
+	using   []string
+	names   []relColumn
+}
+
+func (v *validator) report(path string, node SQLer, err error, format string, args ...interface{}) {
+	v.diags = append(v.diags, Diagnostic{
+		Path:    path,
+		Node:    node,
+		Message: fmt.Sprintf(format, args...),
+		Err:     err,
+	})
+}
+
+// validate checks q and returns the columns of its result. It returns nil
+// when they are not known.
+func (v *validator) validate(q SQLer, path string, outer *scope) []relColumn {
+	switch q := q.(type) {
+	case Select:
+		return v.validateSelect(q, joinPath(path, "select"), outer)
+	case union:
+		return v.validateUnion(q, joinPath(path, "union"), outer)
+	case Insert:
+		return v.validateInsert(q, joinPath(path, "insert"), outer)
+	case Update:
+		return v.validateUpdate(q, joinPath(path, "update"), outer)
+	case Delete:
+		return v.validateDelete(q, joinPath(path, "delete"), outer)
+	default:
+		Walk(q, VisitFunc(func(node SQLer) bool {
+			if isValidated(node) {
+				v.validate(node, path, outer)
+				return false
+			}
+			return true
+		}))
+		return nil
+	}
+}
+
+func (v *validator) validateSelect(s Select, path string, outer *scope) []relColumn {
+	sc := &scope{parent: outer}
+	v.defineCtes(s.ctes, path, sc)
+	for i, q := range s.queries {
+		at := fmt.Sprintf("%s.from[%d]", path, i)
+		src := v.source(q.table, at, sc)
+		switch q.join {
+		case outerLeft:
+			src = src.orNull()
+		case outerRight:
+			for j := range sc.sources {
+				sc.sources[j] = sc.sources[j].orNull()
+			}
+		}
+		sc.sources = append(sc.sources, src)
+		if l, ok := q.cdt.(list); ok {
+			v.checkUsing(l, at+".using", sc)
+		} else {
+			v.checkExpr(q.cdt, at+".on", sc, false)
+		}
+	}
+	var (
+		columns []relColumn
+		known   = true
+		index   int
+	)
+	if s.columnsCount() == 0 {
+		columns, known = expand("", sc)
+	}
+	for _, q := range s.queries {
+		for _, c := range q.columns {
+			v.checkExpr(c, fmt.Sprintf("%s.columns[%d]", path, index), sc, false)
+			index++
+			if id, ok := c.(ident); ok && id.name == "*" {
+				var qualifier string
+				if n := len(id.parents); n > 0 {
+					qualifier = id.parents[n-1]
+				}
+				cs, ok := expand(qualifier, sc)
+				columns, known = append(columns, cs...), known && ok
+				continue
+			}
+			columns = append(columns, v.resultColumn(c, sc))
+		}
+	}
+	sc.names = columns
+	v.checkExpr(s.where, path+".where", sc, false)
+	for i, g := range s.groupby {
+		v.checkExpr(g, fmt.Sprintf("%s.groupby[%d]", path, i), sc, true)
+	}
+	v.checkExpr(s.having, path+".having", sc, true)
+	for i, w := range s.windows {
+		v.checkExpr(w, fmt.Sprintf("%s.window[%d]", path, i), sc, true)
+	}
+	for i, o := range s.orderby {
+		v.checkExpr(o, fmt.Sprintf("%s.orderby[%d]", path, i), sc, true)
+	}
+	if !known {
+		return nil
+	}
+	return columns
+}
+
+func (v *validator) validateUnion(u union, path string, outer *scope) []relColumn {
+	left := v.validate(u.left, path+".left", outer)
+	right := v.validate(u.right, path+".right", outer)
+	v.compareColumns(left, right, path+".right", u.right)
+	return left
+}
+
+// compareColumns reports the columns of the right branch of a union whose
+// type disagree with the type of the same column in the left branch.
+func (v *validator) compareColumns(left, right []relColumn, path string, node SQLer) {
+	for i := 0; i < len(left) && i < len(right); i++ {
+		lf, rf := typeFamily(left[i].kind), typeFamily(right[i].kind)
+		if lf == "" || rf == "" || lf == rf {
+			continue
+		}
+		v.report(fmt.Sprintf("%s.columns[%d]", path, i), node, ErrType, "type %s does not match type %s of the first query", rf, lf)
+	}
+}
+
+func (v *validator) validateInsert(i Insert, path string, outer *scope) []relColumn {
+	sc := &scope{parent: outer}
+	v.defineCtes(i.ctes, path, sc)
+	// the values can not refer to the columns of the table
+	values := &scope{parent: outer, ctes: sc.ctes}
+
+	target := v.source(i.table, path+".table", sc)
+	sc.sources = append(sc.sources, target)
+
+	var names []string
+	for j, c := range i.columns {
+		id, ok := c.(ident)
+		if !ok {
+			continue
+		}
+		names = append(names, id.name)
+		if _, ok := target.column(id.name); target.known && !ok {
+			v.report(fmt.Sprintf("%s.columns[%d]", path, j), c, ErrColumn, "unknown column %s.%s", target.name, id.name)
+		}
+	}
+	if target.table != nil && len(names) > 0 {
+		for _, c := range target.table.columns {
+			if !isRequired(c) || hasName(names, c.name) {
+				continue
+			}
+			v.report(path+".columns", NewIdent(c.name), ErrColumn, "column %s.%s is not nullable and has no default value", target.name, c.name)
+		}
+	}
+	for j, row := range i.values {
+		at := fmt.Sprintf("%s.values[%d]", path, j)
+		for k, e := range row {
+			v.checkExpr(e, fmt.Sprintf("%s[%d]", at, k), values, false)
+		}
+		if len(names) == 0 && target.known && len(row) != len(target.columns) {
+			v.report(at, nil, ErrColumn, "%d values given for %d columns", len(row), len(target.columns))
+		}
+	}
+	if i.query != nil {
+		cols := v.validate(i.query, path, values)
+		if len(names) == 0 && target.known && cols != nil && len(cols) != len(target.columns) {
+			v.report(path+".select", i.query, ErrColumn, "%d columns selected for %d columns", len(cols), len(target.columns))
+		}
+	}
+	if c, ok := i.conflict.(conflict); ok {
+		v.validateConflict(c, path+".conflict", target, sc)
+	}
+	return v.returning(i.returning, path, sc)
+}
+
+func (v *validator) validateConflict(c conflict, path string, target relation, sc *scope) {
+	if t, ok := c.target.(conflictTarget); ok {
+		for j, col := range t.columns {
+			v.checkTarget(col, fmt.Sprintf("%s.columns[%d]", path, j), target)
+		}
+	}
+	a, ok := c.action.(conflictAction)
+	if !ok {
+		return
+	}
+	v.checkSet(a.set, path, target, sc)
+	v.checkExpr(a.where, path+".where", sc, false)
+}
+
+func (v *validator) validateUpdate(u Update, path string, outer *scope) []relColumn {
+	sc := &scope{parent: outer}
+	v.defineCtes(u.ctes, path, sc)
+	target := v.source(u.table, path+".table", sc)
+	sc.sources = append(sc.sources, target)
+	v.sources(u.sources, path, sc)
+	v.checkSet(u.columns, path, target, sc)
+	v.checkExpr(u.where, path+".where", sc, false)
+	return v.returning(u.returning, path, sc)
+}
+
+func (v *validator) validateDelete(d Delete, path string, outer *scope) []relColumn {
+	sc := &scope{parent: outer}
+	v.defineCtes(d.ctes, path, sc)
+	sc.sources = append(sc.sources, v.source(d.table, path+".table", sc))
+	v.sources(d.sources, path, sc)
+	v.checkExpr(d.where, path+".where", sc, false)
+	return v.returning(d.returning, path, sc)
+}
+
+func (v *validator) sources(queries []query, path string, sc *scope) {
+	for j, q := range queries {
+		at := fmt.Sprintf("%s.from[%d]", path, j)
+		sc.sources = append(sc.sources, v.source(q.table, at, sc))
+		v.checkExpr(q.cdt, at+".on", sc, false)
+	}
+}
+
+// checkSet checks the assignments of an UPDATE: the assigned columns should
+// belong to target.
+func (v *validator) checkSet(set []SQLer, path string, target relation, sc *scope) {
+	for j, s := range set {
+		at := fmt.Sprintf("%s.set[%d]", path, j)
+		c, ok := s.(compare)
+		if !ok {
+			v.checkExpr(s, at, sc, false)
+			continue
+		}
+		v.checkTarget(c.left, at, target)
+		v.checkExpr(c.right, at, sc, false)
+	}
+}
+
+func (v *validator) checkTarget(col SQLer, path string, target relation) {
+	id, ok := col.(ident)
+	if !ok || !target.known {
+		return
+	}
+	if _, ok := target.column(id.name); !ok {
+		v.report(path, col, ErrColumn, "unknown column %s.%s", target.name, id.name)
+	}
+}
+
+func (v *validator) returning(list []SQLer, path string, sc *scope) []relColumn {
+	if len(list) == 0 {
+		return nil
+	}
+	var columns []relColumn
+	for j, r := range list {
+		v.checkExpr(r, fmt.Sprintf("%s.returning[%d]", path, j), sc, false)
+		columns = append(columns, v.resultColumn(r, sc))
+	}
+	return columns
+}
+
+// defineCtes checks the common table expressions of a statement and adds
+// them to sc. The body of a recursive expression can refer to itself.
+func (v *validator) defineCtes(ctes []SQLer, path string, sc *scope) {
+	for i, c := range ctes {
+		x, ok := c.(cte)
+		if !ok {
+			continue
+		}
+		var (
+			at    = fmt.Sprintf("%s.with[%d]", path, i)
+			names []string
+		)
+		for _, col := range x.columns {
+			if id, ok := col.(ident); ok {
+				names = append(names, id.name)
+			}
+		}
+		if u, ok := x.inner.(union); ok && x.recursive {
+			at = joinPath(at, "union")
+			left := v.validate(u.left, at+".left", sc)
+			sc.ctes = append(sc.ctes, newCteRelation(x.name, left, names))
+			right := v.validate(u.right, at+".right", sc)
+			v.compareColumns(left, right, at+".right", u.right)
+			continue
+		}
+		columns := v.validate(x.inner, at, sc)
+		sc.ctes = append(sc.ctes, newCteRelation(x.name, columns, names))
+	}
+}
+
+func newCteRelation(name string, columns []relColumn, names []string) relation {
+	r := relation{
+		name:    name,
+		columns: columns,
+		known:   columns != nil,
+	}
+	if len(names) == 0 {
+		return r
+	}
+	r.columns, r.known = nil, true
+	for i, n := range names {
+		c := relColumn{name: n, nullable: true}
+		if i < len(columns) {
+			c.kind, c.nullable = columns[i].kind, columns[i].nullable
+		}
+		r.columns = append(r.columns, c)
+	}
+	return r
+}
+
+// source returns the relation of a table given in a FROM clause, a join or as
+// the target of a statement.
+func (v *validator) source(src SQLer, path string, sc *scope) relation {
+	switch x := src.(type) {
+	case ident:
+		name := unquote(x.name)
+		if len(x.parents) == 0 {
+			for s := sc; s != nil; s = s.parent {
+				for _, r := range s.ctes {
+					if strings.EqualFold(r.name, name) {
+						return r
+					}
+				}
+			}
+		}
+		t, ok := v.schema.Table(name)
+		if !ok {
+			v.report(path, src, ErrIdent, "unknown table %s", name)
+			return relation{name: name}
+		}
+		r := tableRelation(t)
+		r.name = name
+		return r
+	case Table:
+		r := v.source(NewIdent(x.name), path, sc)
+		if x.alias != "" {
+			r.name = x.alias
+		}
+		return r
+	case alias:
+		r := v.source(x.SQLer, path, sc)
+		r.name = x.name
+		return r
+	case Select, union:
+		columns := v.validate(x, path, sc.parent)
+		return relation{
+			columns: columns,
+			known:   columns != nil,
+		}
+	default:
+		return relation{}
+	}
+}
+
+// checkUsing checks that the columns of a USING clause belong to the last
+// joined relation and to one of the relations joined before it.
+func (v *validator) checkUsing(l list, path string, sc *scope) {
+	var (
+		n    = len(sc.sources) - 1
+		last = sc.sources[n]
+	)
+	for _, p := range l.parts {
+		id, ok := p.(ident)
+		if !ok {
+			continue
+		}
+		sc.using = append(sc.using, id.name)
+		if _, ok := last.column(id.name); last.known && !ok {
+			v.report(path, p, ErrColumn, "unknown column %s.%s", last.name, id.name)
+			continue
+		}
+		var found, unknown bool
+		for _, r := range sc.sources[:n] {
+			_, ok := r.column(id.name)
+			found = found || ok
+			unknown = unknown || !r.known
+		}
+		if !found && !unknown {
+			v.report(path, p, ErrColumn, "unknown column %s", id.name)
+		}
+	}
+}
+
+// checkExpr reports the columns of expr that can not be resolved in sc. The
+// names of the columns of the result are accepted if names is true.
+// Statements used in expr are validated with sc as outer scope.
+func (v *validator) checkExpr(expr SQLer, path string, sc *scope, names bool) {
+	if expr == nil {
+		return
+	}
+	Walk(expr, VisitFunc(func(node SQLer) bool {
+		var err error
+		switch n := node.(type) {
+		case ident:
+			_, err = v.resolve(n.name, n.parents, sc, names)
+		case Column:
+			_, err = v.resolve(n.name, n.Parents(), sc, names)
+		case orderby:
+			if n.expr != nil {
+				return true
+			}
+			parts := strings.Split(n.column, ".")
+			last := len(parts) - 1
+			_, err = v.resolve(parts[last], parts[:last], sc, names)
+		default:
+			if isValidated(node) {
+				v.validate(node, path, sc)
+				return false
+			}
+			return true
+		}
+		if d, ok := err.(Diagnostic); ok {
+			d.Path, d.Node = path, node
+			v.diags = append(v.diags, d)
+		}
+		return true
+	}))
+}
+
+var pseudoColumns = []string{
+	"CURRENT_DATE",
+	"CURRENT_TIME",
+	"CURRENT_TIMESTAMP",
+	"CURRENT_USER",
+	"DEFAULT",
+	"LOCALTIME",
+	"LOCALTIMESTAMP",
+}
+
+// resolve returns the column referred to by name, qualified by parents, in
+// the relations of sc or of its parents. The error returned is a Diagnostic
+// without path.
+func (v *validator) resolve(name string, parents []string, sc *scope, names bool) (relColumn, error) {
+	name = unquote(name)
+	if len(parents) > 0 {
+		qualifier := unquote(parents[len(parents)-1])
+		for s := sc; s != nil; s = s.parent {
+			for _, r := range s.sources {
+				if !strings.EqualFold(r.name, qualifier) {
+					continue
+				}
+				if name == "*" || !r.known {
+					return relColumn{name: name}, nil
+				}
+				if c, ok := r.column(name); ok {
+					return c, nil
+				}
+				return relColumn{}, Diagnostic{
+					Message: fmt.Sprintf("unknown column %s.%s", qualifier, name),
+					Err:     ErrColumn,
+				}
+			}
+		}
+		return relColumn{}, Diagnostic{
+			Message: fmt.Sprintf("unknown table or alias %s", qualifier),
+			Err:     ErrIdent,
+		}
+	}
+	if name == "*" || hasName(pseudoColumns, name) {
+		return relColumn{name: name}, nil
+	}
+	for s := sc; s != nil; s = s.parent {
+		var (
+			found   []relColumn
+			unknown bool
+		)
+		for _, r := range s.sources {
+			if !r.known {
+				unknown = true
+				continue
+			}
+			if c, ok := r.column(name); ok {
+				found = append(found, c)
+			}
+		}
+		switch {
+		case len(found) == 1:
+			return found[0], nil
+		case len(found) > 1 && hasName(s.using, name):
+			return found[0], nil
+		case len(found) > 1:
+			return relColumn{}, Diagnostic{
+				Message: fmt.Sprintf("ambiguous column %s", name),
+				Err:     ErrColumn,
+			}
+		case unknown:
+			return relColumn{name: name}, nil
+		}
+		if s == sc && names {
+			for _, c := range s.names {
+				if strings.EqualFold(c.name, name) {
+					return c, nil
+				}
+			}
+		}
+	}
+	return relColumn{}, Diagnostic{
+		Message: fmt.Sprintf("unknown column %s", name),
+		Err:     ErrColumn,
+	}
+}
+
+// resultColumn returns the name, the type and the nullability of a column of
+// the result of a query. Only the identifiers and the aliased expressions give
+// a name.
+func (v *validator) resultColumn(expr SQLer, sc *scope) relColumn {
+	c := v.infer(expr, sc)
+	switch e := expr.(type) {
+	case alias:
+		c.name = e.name
+	case ident:
+		c.name = unquote(e.name)
+	case Column:
+		c.name = e.name
+	default:
+		c.name = ""
+	}
+	return c
+}
+
+// typeFamily returns the name of the group of types that kind belongs to.
+// Types of a same group can be compared with each other. It returns an empty
+// string if kind is not known.
+func typeFamily(kind SQLer) string {
+	t, ok := kind.(columnType)
+	if !ok {
+		return ""
+	}
+	switch t.kind {
+	case typeInt, typeSmallInt, typeBigInt, typeDecimal, typeReal, typeDouble:
+		return "numeric"
+	case typeText, typeVarchar, typeChar:
+		return "text"
+	case typeBool:
+		return "boolean"
+	case typeDate, typeTimestamp:
+		return "date"
+	case typeTime:
+		return "time"
+	case typeBlob:
+		return "blob"
+	case typeJSON:
+		return "json"
+	case typeUUID:
+		return "uuid"
+	case typeInterval:
+		return "interval"
+	default:
+		return ""
+	}
+}
+
+// expand returns the columns selected by * qualified by qualifier, or by all
+// the relations of sc if qualifier is empty. It returns false if the columns
+// of one of the relations are not known.
+func expand(qualifier string, sc *scope) ([]relColumn, bool) {
+	var columns []relColumn
+	for _, r := range sc.sources {
+		if qualifier != "" && !strings.EqualFold(r.name, unquote(qualifier)) {
+			continue
+		}
+		if !r.known {
+			return nil, false
+		}
+		columns = append(columns, r.columns...)
+	}
+	return columns, true
+}
+
+func isValidated(q SQLer) bool {
+	switch q.(type) {
+	case Select, union, Insert, Update, Delete:
+		return true
+	default:
+		return false
+	}
+}
+
+// isRequired reports whether a value should be given for c when inserting a
+// row. Primary keys not declared NOT NULL are not required since they can be
+// generated by the engine.
+func isRequired(c columnDef) bool {
+	return c.null == notNullable && c.value == nil && !c.auto
+}
+
+func hasName(list []string, name string) bool {
+	for _, n := range list {
+		if strings.EqualFold(n, name) {
+			return true
+		}
+	}
+	return false
+}
+
+func unquote(name string) string {
+	if n := len(name); n >= 2 && isQuote(rune(name[0])) && name[0] == name[n-1] {
+		return name[1 : n-1]
+	}
+	return name
+}
+
+func joinPath(path, elem string) string {
+	if path == "" {
+		return elem
+	}
+	return path + "." + elem
+}
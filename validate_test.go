@@ -0,0 +1,241 @@
+package quel
+
+import (
+	"errors"
+	"testing"
+)
+
+const testSchema = `
+CREATE TABLE users (
+	id INT PRIMARY KEY,
+	name VARCHAR(64) NOT NULL,
+	email VARCHAR(255) NOT NULL,
+	created TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+CREATE TABLE posts (
+	id INT PRIMARY KEY,
+	user INT NOT NULL REFERENCES users(id),
+	title TEXT NOT NULL,
+	body TEXT
+);
+CREATE INDEX posts_user ON posts(user);
+`
+
+func TestLoadSchema(t *testing.T) {
+	s, err := LoadSchema(testSchema, Default)
+	if err != nil {
+		t.Fatalf("error loading schema! %s", err)
+	}
+	if ts := s.Tables(); len(ts) != 2 || ts[0].Name() != "users" || ts[1].Name() != "posts" {
+		t.Fatalf("tables of schema mismatched")
+	}
+	if _, ok := s.Table("POSTS"); !ok {
+		t.Errorf("posts: table not found")
+	}
+	if _, ok := s.Table("comments"); ok {
+		t.Errorf("comments: unexpected table found")
+	}
+	if _, err := LoadSchema(testSchema+"CREATE TABLE users (id INT);", Default); err == nil {
+		t.Errorf("expected error when table is defined twice")
+	}
+}
+
+func TestValidate(t *testing.T) {
+	schema, err := LoadSchema(testSchema, Default)
+	if err != nil {
+		t.Fatalf("error loading schema! %s", err)
+	}
+	data := []struct {
+		Name  string
+		Query func() (SQLer, error)
+		Paths []string
+		Err   error
+	}{
+		{
+			Name: "select",
+			Query: func() (SQLer, error) {
+				return NewSelect("users", SelectColumns("id", "name"), SelectWhere(Equal(NewIdent("email"), Arg("email", ""))))
+			},
+		},
+		{
+			Name: "unknown-table",
+			Query: func() (SQLer, error) {
+				return NewSelect("comments", SelectColumns("id"))
+			},
+			Paths: []string{"select.from[0]"},
+			Err:   ErrIdent,
+		},
+		{
+			Name: "unknown-column",
+			Query: func() (SQLer, error) {
+				return NewSelect("users", SelectColumns("id", "title"), SelectOrderBy(Asc("age")))
+			},
+			Paths: []string{"select.columns[1]", "select.orderby[0]"},
+			Err:   ErrColumn,
+		},
+		{
+			Name: "alias",
+			Query: func() (SQLer, error) {
+				q, err := NewSelect("users", SelectAlias("u"), SelectColumn(NewIdent("name", "u")))
+				if err != nil {
+					return nil, err
+				}
+				return q.LeftInnerJoin(Alias("p", NewIdent("posts")), Equal(NewIdent("id", "u"), NewIdent("user", "p")), SelectColumn(NewIdent("title", "p")))
+			},
+		},
+		{
+			Name: "alias-unknown",
+			Query: func() (SQLer, error) {
+				return NewSelect("users", SelectAlias("u"), SelectColumn(NewIdent("name", "users")), SelectColumn(NewIdent("title", "u")))
+			},
+			Paths: []string{"select.columns[0]", "select.columns[1]"},
+		},
+		{
+			Name: "ambiguous",
+			Query: func() (SQLer, error) {
+				q, err := NewSelect("users", SelectColumns("name"))
+				if err != nil {
+					return nil, err
+				}
+				return q.LeftInnerJoin(NewIdent("posts"), Equal(NewIdent("id", "users"), NewIdent("user", "posts")), SelectColumns("id"))
+			},
+			Paths: []string{"select.columns[1]"},
+			Err:   ErrColumn,
+		},
+		{
+			Name: "using",
+			Query: func() (SQLer, error) {
+				q, err := NewSelect("users", SelectColumns("id"))
+				if err != nil {
+					return nil, err
+				}
+				return q.LeftInnerJoin(NewIdent("posts"), NewList(NewIdent("id")))
+			},
+		},
+		{
+			Name: "output-alias",
+			Query: func() (SQLer, error) {
+				return NewSelect("posts",
+					SelectColumn(NewIdent("user")),
+					SelectColumn(Alias("total", Count(NewIdent("id")))),
+					SelectGroupBy(NewIdent("user")),
+					SelectOrderBy(Desc("total")),
+				)
+			},
+		},
+		{
+			Name: "correlated",
+			Query: func() (SQLer, error) {
+				inner, err := NewSelect("posts", SelectColumns("id"), SelectWhere(Equal(NewIdent("user"), NewIdent("id", "u"))))
+				if err != nil {
+					return nil, err
+				}
+				return NewSelect("users", SelectAlias("u"), SelectColumns("name"), SelectWhere(Exists(inner)))
+			},
+		},
+		{
+			Name: "subquery-unknown",
+			Query: func() (SQLer, error) {
+				inner, err := NewSelect("posts", SelectColumns("user"), SelectWhere(Equal(NewIdent("status"), Arg("status", ""))))
+				if err != nil {
+					return nil, err
+				}
+				return NewSelect("users", SelectColumns("name"), SelectWhere(In(NewIdent("id"), inner)))
+			},
+			Paths: []string{"select.where.select.where"},
+			Err:   ErrColumn,
+		},
+		{
+			Name: "cte",
+			Query: func() (SQLer, error) {
+				inner, err := NewSelect("posts", SelectColumns("user", "title"))
+				if err != nil {
+					return nil, err
+				}
+				return NewSelect("titles", SelectWith("titles", inner), SelectColumns("title", "body"))
+			},
+			Paths: []string{"select.columns[1]"},
+			Err:   ErrColumn,
+		},
+		{
+			Name: "insert",
+			Query: func() (SQLer, error) {
+				return NewInsert("users", InsertColumns("id", "name", "email"), InsertValues(Arg("id", 1), Arg("name", ""), Arg("email", "")))
+			},
+		},
+		{
+			Name: "insert-required",
+			Query: func() (SQLer, error) {
+				return NewInsert("users", InsertColumns("id", "name", "age"), InsertValues(Arg("id", 1), Arg("name", ""), Arg("age", 0)))
+			},
+			Paths: []string{"insert.columns[2]", "insert.columns"},
+			Err:   ErrColumn,
+		},
+		{
+			Name: "update",
+			Query: func() (SQLer, error) {
+				return NewUpdate("posts", UpdateColumn("title", Arg("title", "")), UpdateColumn("views", NewLiteral(0)), UpdateWhere(Equal(NewIdent("id"), Arg("id", 1))))
+			},
+			Paths: []string{"update.set[1]"},
+			Err:   ErrColumn,
+		},
+		{
+			Name: "delete",
+			Query: func() (SQLer, error) {
+				return NewDelete("posts", DeleteWhere(Equal(NewIdent("author"), Arg("author", 1))))
+			},
+			Paths: []string{"delete.where"},
+			Err:   ErrColumn,
+		},
+		{
+			Name: "union",
+			Query: func() (SQLer, error) {
+				left, err := NewSelect("users", SelectColumns("id", "name"))
+				if err != nil {
+					return nil, err
+				}
+				right, err := NewSelect("posts", SelectColumns("user", "title"))
+				if err != nil {
+					return nil, err
+				}
+				return Union(left, right)
+			},
+		},
+		{
+			Name: "union-mismatch",
+			Query: func() (SQLer, error) {
+				left, err := NewSelect("users", SelectColumns("id", "created"))
+				if err != nil {
+					return nil, err
+				}
+				right, err := NewSelect("posts", SelectColumns("title", "user"))
+				if err != nil {
+					return nil, err
+				}
+				return Union(left, right)
+			},
+			Paths: []string{"union.right.columns[0]", "union.right.columns[1]"},
+			Err:   ErrType,
+		},
+	}
+	for _, d := range data {
+		t.Run(d.Name, func(t *testing.T) {
+			q, err := d.Query()
+			if err != nil {
+				t.Fatalf("error creating query! %s", err)
+			}
+			diags := Validate(q, schema)
+			if len(diags) != len(d.Paths) {
+				t.Fatalf("diagnostics mismatched! want %d, got %d (%v)", len(d.Paths), len(diags), diags)
+			}
+			for i, g := range diags {
+				if g.Path != d.Paths[i] {
+					t.Errorf("path mismatched! want %s, got %s", d.Paths[i], g.Path)
+				}
+				if d.Err != nil && !errors.Is(g, d.Err) {
+					t.Errorf("%s: unexpected error %s", g.Path, g.Err)
+				}
+			}
+		})
+	}
+}
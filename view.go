@@ -0,0 +1,151 @@
+package quel
+
+import (
+	"fmt"
+	"strings"
+)
+
+type CreateViewOption func(*CreateView) error
+
+// ViewColumns sets the names of the columns of the view. Their number should
+// match the number of columns selected by the query of the view.
+func ViewColumns(columns ...string) CreateViewOption {
+	return func(v *CreateView) error {
+		for _, c := range columns {
+			if !isValidIdentifier(c) {
+				return fmt.Errorf("view: %w %q", ErrIdent, c)
+			}
+			v.columns = append(v.columns, c)
+		}
+		return nil
+	}
+}
+
+// ViewReplace replaces the view if it already exists. It is not supported by
+// SQLite nor for materialized views.
+func ViewReplace() CreateViewOption {
+	return func(v *CreateView) error {
+		v.replace = true
+		return nil
+	}
+}
+
+// ViewIfNotExists is not supported by MySQL.
+func ViewIfNotExists() CreateViewOption {
+	return func(v *CreateView) error {
+		v.exists = true
+		return nil
+	}
+}
+
+// CreateView is the CREATE VIEW statement and, when created with
+// NewCreateMaterializedView, the CREATE MATERIALIZED VIEW statement.
+type CreateView struct {
+	name         SQLer
+	query        SQLer
+	columns      []string
+	replace      bool
+	exists       bool
+	materialized bool
+}
+
+func NewCreateView(name string, q Select, options ...CreateViewOption) (CreateView, error) {
+	return newCreateView(name, q, false, options)
+}
+
+// NewCreateMaterializedView creates a view whose rows are stored. It is only
+// supported by Postgres.
+func NewCreateMaterializedView(name string, q Select, options ...CreateViewOption) (CreateView, error) {
+	return newCreateView(name, q, true, options)
+}
+
+func newCreateView(name string, q Select, materialized bool, options []CreateViewOption) (CreateView, error) {
+	v := CreateView{
+		name:         NewIdent(name),
+		query:        q,
+		materialized: materialized,
+	}
+	if !isValidIdentifier(name) {
+		return v, fmt.Errorf("view: %w %q", ErrIdent, name)
+	}
+	for _, opt := range options {
+		if err := opt(&v); err != nil {
+			return v, err
+		}
+	}
+	if v.replace && v.exists {
+		return v, fmt.Errorf("view: %w: replace and if not exists can not be combined", ErrSyntax)
+	}
+	if v.replace && v.materialized {
+		return v, fmt.Errorf("view: %w: materialized view can not be replaced", ErrSyntax)
+	}
+	if n := q.columnsCount(); n > 0 && len(v.columns) > 0 && n != len(v.columns) {
+		return v, fmt.Errorf("view: %w: select returns %d columns, %d expected", ErrColumn, n, len(v.columns))
+	}
+	return v, nil
+}
+
+func (v CreateView) SQL() (string, []interface{}, error) {
+	return SQLDialect(v, Default)
+}
+
+func (v CreateView) SQLDialect(d Dialect) (string, []interface{}, error) {
+	return SQLDialect(v, d)
+}
+
+func (v CreateView) render(r *renderer) (string, []interface{}, error) {
+	switch {
+	case v.materialized && !r.is(Postgres) && !r.is(Default):
+		return "", nil, fmt.Errorf("create view: materialized view only supported by Postgres")
+	case v.replace && r.is(SQLite):
+		return "", nil, fmt.Errorf("create view: or replace not supported by SQLite")
+	case v.exists && r.is(MySQL):
+		return "", nil, fmt.Errorf("create view: if not exists not supported by MySQL")
+	}
+	var b strings.Builder
+	b.WriteString(r.keyword("CREATE") + " ")
+	if v.replace {
+		b.WriteString(r.keyword("OR REPLACE") + " ")
+	}
+	if v.materialized {
+		b.WriteString(r.keyword("MATERIALIZED") + " ")
+	}
+	b.WriteString(r.keyword("VIEW") + " ")
+	if v.exists {
+		b.WriteString(r.keyword("IF NOT EXISTS") + " ")
+	}
+	sql, err := renderDDL(r, v.name)
+	if err != nil {
+		return "", nil, err
+	}
+	b.WriteString(sql)
+	if len(v.columns) > 0 {
+		b.WriteString(r.idents(v.columns))
+	}
+	sql, err = renderDDL(r, v.query)
+	if err != nil {
+		return "", nil, err
+	}
+	b.WriteString(" " + r.keyword("AS"))
+	b.WriteString(r.sep())
+	b.WriteString(sql)
+	return b.String(), nil, nil
+}
+
+// NewCreateTableAs creates a table filled with the rows returned by q. Only
+// the option CreateIfNotExists can be given.
+func NewCreateTableAs(table string, q Select, options ...CreateTableOption) (CreateTable, error) {
+	c := CreateTable{
+		table: NewIdent(table),
+		query: q,
+	}
+	for _, opt := range options {
+		if err := opt(&c); err != nil {
+			return c, err
+		}
+	}
+	if len(c.columns) > 0 || len(c.constraints) > 0 {
+		return c, fmt.Errorf("%w: columns can not be given with a query", ErrSyntax)
+	}
+	return c, nil
+}
@@ -0,0 +1,516 @@
+package quel
+
+// Visitor is called by Walk for each node of a query. If Visit returns a nil
+// Visitor, the children of node are not visited. Otherwise, Walk visits the
+// children with the returned Visitor and calls its Visit method with nil once
+// done.
+type Visitor interface {
+	Visit(node SQLer) Visitor
+}
+
+// VisitFunc adapts a function to a Visitor. The children of a node are
+// visited only if the function returns true.
+type VisitFunc func(SQLer) bool
+
+func (f VisitFunc) Visit(node SQLer) Visitor {
+	if node == nil || !f(node) {
+		return nil
+	}
+	return f
+}
+
+// Walk traverses q in depth first order, calling v.Visit for each node.
+func Walk(q SQLer, v Visitor) {
+	if q == nil {
+		return
+	}
+	if v = v.Visit(q); v == nil {
+		return
+	}
+	mapChildren(q, func(s SQLer) SQLer {
+		Walk(s, v)
+		return s
+	})
+	v.Visit(nil)
+}
+
+// Rewrite returns a copy of q where each node has been replaced by the result
+// of fn. The children of a node are rewritten before the node itself is given
+// to fn. fn should return its argument to keep a node unchanged. q is left
+// unmodified.
+func Rewrite(q SQLer, fn func(SQLer) SQLer) SQLer {
+	if q == nil {
+		return nil
+	}
+	q = mapChildren(q, func(s SQLer) SQLer {
+		return Rewrite(s, fn)
+	})
+	return fn(q)
+}
+
+// IdentNode is implemented by the identifiers created by NewIdent and by the
+// columns of a Table.
+type IdentNode interface {
+	SQLer
+	Name() string
+	Parents() []string
+}
+
+// AliasNode is implemented by the nodes created by Alias.
+type AliasNode interface {
+	SQLer
+	Name() string
+	Expr() SQLer
+}
+
+// ListNode is implemented by the lists created by NewList and Using.
+type ListNode interface {
+	SQLer
+	Items() []SQLer
+}
+
+// LiteralNode is implemented by the literals created by NewLiteral.
+type LiteralNode interface {
+	SQLer
+	Value() interface{}
+}
+
+// ArgNode is implemented by the arguments created by Arg and by the
+// placeholders returned by Parse. Value returns nil for the latter.
+type ArgNode interface {
+	SQLer
+	Name() string
+	Value() interface{}
+}
+
+// BinaryNode is implemented by the comparisons, the arithmetic operations,
+// the concatenations, the AND and OR of predicates and the unions of queries.
+type BinaryNode interface {
+	SQLer
+	Left() SQLer
+	Right() SQLer
+	Operator() string
+}
+
+// NotNode is implemented by the nodes created by Not.
+type NotNode interface {
+	SQLer
+	Expr() SQLer
+}
+
+// BetweenNode is implemented by the nodes created by Between.
+type BetweenNode interface {
+	SQLer
+	Expr() SQLer
+	Lower() SQLer
+	Upper() SQLer
+}
+
+// FunctionNode is implemented by the function calls created by Func.
+type FunctionNode interface {
+	SQLer
+	Name() string
+	Args() []SQLer
+}
+
+// CaseNode is implemented by the CASE expressions.
+type CaseNode interface {
+	SQLer
+	Expr() SQLer
+	When() []SQLer
+	Then() []SQLer
+	Else() SQLer
+}
+
+// CteNode is implemented by the common table expressions of a query.
+type CteNode interface {
+	SQLer
+	Name() string
+	Recursive() bool
+	Columns() []SQLer
+	Query() SQLer
+}
+
+// ExistsNode is implemented by the nodes created by Exists.
+type ExistsNode interface {
+	SQLer
+	Query() SQLer
+}
+
+// OrderNode is implemented by the nodes created by Asc and Desc. Column is
+// empty if the rows are sorted by an expression, given by Expr.
+type OrderNode interface {
+	SQLer
+	Column() string
+	Expr() SQLer
+	Order() string
+}
+
+// WindowNode is implemented by the window functions created by Over and the
+// window specifications created by Window. Func returns nil for the latter.
+type WindowNode interface {
+	SQLer
+	Func() SQLer
+	Ref() string
+	PartitionBy() []SQLer
+	OrderBy() []SQLer
+}
+
+func (i ident) Name() string         { return i.name }
+func (i ident) Parents() []string    { return append([]string{}, i.parents...) }
+func (a alias) Name() string         { return a.name }
+func (a alias) Expr() SQLer          { return a.SQLer }
+func (i list) Items() []SQLer        { return append([]SQLer{}, i.parts...) }
+func (i literal) Value() interface{} { return i.value }
+func (a arg) Name() string           { return a.name }
+func (a arg) Value() interface{}     { return a.value }
+
+func (p placeholder) Name() string       { return p.name }
+func (p placeholder) Value() interface{} { return nil }
+
+func (c compare) Left() SQLer      { return c.left }
+func (c compare) Right() SQLer     { return c.right }
+func (c compare) Operator() string { return cmpops[c.op] }
+
+func (a arithmetic) Left() SQLer      { return a.left }
+func (a arithmetic) Right() SQLer     { return a.right }
+func (a arithmetic) Operator() string { return mathops[a.op] }
+func (c concat) Left() SQLer          { return c.left }
+func (c concat) Right() SQLer         { return c.right }
+func (c concat) Operator() string     { return "||" }
+
+func (a and) Left() SQLer      { return a.left }
+func (a and) Right() SQLer     { return a.right }
+func (a and) Operator() string { return "AND" }
+
+func (o or) Left() SQLer      { return o.left }
+func (o or) Right() SQLer     { return o.right }
+func (o or) Operator() string { return "OR" }
+
+func (u union) Left() SQLer  { return u.left }
+func (u union) Right() SQLer { return u.right }
+func (u union) Operator() string {
+	if u.all {
+		return "UNION ALL"
+	}
+	return "UNION"
+}
+
+func (n not) Expr() SQLer { return n.right }
+
+func (n negate) Expr() SQLer   { return n.expr }
+func (d distinct) Expr() SQLer { return d.expr }
+
+func (b between) Expr() SQLer  { return b.value }
+func (b between) Lower() SQLer { return b.left }
+func (b between) Upper() SQLer { return b.right }
+
+func (f function) Name() string  { return f.name }
+func (f function) Args() []SQLer { return append([]SQLer{}, f.args...) }
+
+func (k kase) Expr() SQLer   { return k.expr }
+func (k kase) When() []SQLer { return append([]SQLer{}, k.test...) }
+func (k kase) Then() []SQLer { return append([]SQLer{}, k.csq...) }
+func (k kase) Else() SQLer   { return k.alt }
+
+func (c cte) Name() string     { return c.name }
+func (c cte) Recursive() bool  { return c.recursive }
+func (c cte) Columns() []SQLer { return append([]SQLer{}, c.columns...) }
+func (c cte) Query() SQLer     { return c.inner }
+
+func (e exist) Query() SQLer { return e.inner }
+
+func (w window) Func() SQLer          { return w.fn }
+func (w window) Ref() string          { return w.ref }
+func (w window) PartitionBy() []SQLer { return append([]SQLer{}, w.partition...) }
+func (w window) OrderBy() []SQLer     { return append([]SQLer{}, w.orderby...) }
+
+func (o orderby) Column() string { return o.column }
+func (o orderby) Expr() SQLer    { return o.expr }
+func (o orderby) Order() string  { return o.order }
+
+// mapSources returns a copy of queries with their table and condition
+// replaced by the result of fn.
+func mapSources(queries []query, fn func(SQLer) SQLer) []query {
+	if queries == nil {
+		return nil
+	}
+	qs := make([]query, len(queries))
+	for i, q := range queries {
+		q.table = fn(q.table)
+		q.cdt = fn(q.cdt)
+		qs[i] = q
+	}
+	return qs
+}
+
+// mapChildren returns a copy of s with each of its children replaced by the
+// result of fn. Nodes without children are returned as is.
+func mapChildren(s SQLer, fn func(SQLer) SQLer) SQLer {
+	apply := func(s SQLer) SQLer {
+		if s == nil {
+			return nil
+		}
+		return fn(s)
+	}
+	applyAll := func(list []SQLer) []SQLer {
+		if list == nil {
+			return nil
+		}
+		res := make([]SQLer, len(list))
+		for i := range list {
+			res[i] = apply(list[i])
+		}
+		return res
+	}
+	switch s := s.(type) {
+	case alias:
+		s.SQLer = apply(s.SQLer)
+		return s
+	case list:
+		s.parts = applyAll(s.parts)
+		return s
+	case compare:
+		s.left, s.right = apply(s.left), apply(s.right)
+		return s
+	case arithmetic:
+		s.left, s.right = apply(s.left), apply(s.right)
+		return s
+	case and:
+		s.left, s.right = apply(s.left), apply(s.right)
+		return s
+	case or:
+		s.left, s.right = apply(s.left), apply(s.right)
+		return s
+	case union:
+		s.left, s.right = apply(s.left), apply(s.right)
+		return s
+	case not:
+		s.right = apply(s.right)
+		return s
+	case between:
+		s.value, s.left, s.right = apply(s.value), apply(s.left), apply(s.right)
+		return s
+	case function:
+		s.args = applyAll(s.args)
+		return s
+	case kase:
+		s.expr = apply(s.expr)
+		s.test = applyAll(s.test)
+		s.csq = applyAll(s.csq)
+		s.alt = apply(s.alt)
+		return s
+	case cte:
+		s.columns = applyAll(s.columns)
+		s.inner = apply(s.inner)
+		return s
+	case window:
+		s.fn = apply(s.fn)
+		s.partition = applyAll(s.partition)
+		s.orderby = applyAll(s.orderby)
+		return s
+	case conflict:
+		s.target, s.action = apply(s.target), apply(s.action)
+		return s
+	case conflictTarget:
+		s.columns = applyAll(s.columns)
+		return s
+	case conflictAction:
+		s.set = applyAll(s.set)
+		s.where = apply(s.where)
+		return s
+	case namedWindow:
+		s.spec = apply(s.spec)
+		return s
+	case concat:
+		s.left, s.right = apply(s.left), apply(s.right)
+		return s
+	case cast:
+		s.expr = apply(s.expr)
+		return s
+	case orderby:
+		s.expr = apply(s.expr)
+		return s
+	case materialize:
+		s.inner = apply(s.inner)
+		return s
+	case exist:
+		s.inner = apply(s.inner)
+		return s
+	case any:
+		s.inner = apply(s.inner)
+		return s
+	case negate:
+		s.expr = apply(s.expr)
+		return s
+	case distinct:
+		s.expr = apply(s.expr)
+		return s
+	case all:
+		s.inner = apply(s.inner)
+		return s
+	case Select:
+		s.ctes = applyAll(s.ctes)
+		qs := make([]query, len(s.queries))
+		for i, q := range s.queries {
+			q.columns = applyAll(q.columns)
+			qs[i] = q
+		}
+		s.queries = mapSources(qs, apply)
+		s.where = apply(s.where)
+		s.groupby = applyAll(s.groupby)
+		s.having = apply(s.having)
+		s.windows = applyAll(s.windows)
+		s.orderby = applyAll(s.orderby)
+		return s
+	case Insert:
+		s.ctes = applyAll(s.ctes)
+		s.table = apply(s.table)
+		s.columns = applyAll(s.columns)
+		values := make([][]SQLer, len(s.values))
+		for i := range s.values {
+			values[i] = applyAll(s.values[i])
+		}
+		s.values = values
+		s.query = apply(s.query)
+		s.conflict = apply(s.conflict)
+		s.returning = applyAll(s.returning)
+		return s
+	case Update:
+		s.ctes = applyAll(s.ctes)
+		s.table = apply(s.table)
+		s.sources = mapSources(s.sources, apply)
+		s.columns = applyAll(s.columns)
+		s.where = apply(s.where)
+		s.returning = applyAll(s.returning)
+		return s
+	case Merge:
+		s.table = apply(s.table)
+		s.source = apply(s.source)
+		s.cdt = apply(s.cdt)
+		s.branches = applyAll(s.branches)
+		s.returning = applyAll(s.returning)
+		return s
+	case mergeBranch:
+		s.cdt = apply(s.cdt)
+		s.action = apply(s.action)
+		return s
+	case mergeAction:
+		s.columns = applyAll(s.columns)
+		s.values = applyAll(s.values)
+		return s
+	case columnDef:
+		s.kind = apply(s.kind)
+		s.value = apply(s.value)
+		s.check = apply(s.check)
+		return s
+	case constraint:
+		s.check = apply(s.check)
+		return s
+	case CreateTable:
+		s.table = apply(s.table)
+		s.query = apply(s.query)
+		s.columns = applyAll(s.columns)
+		s.constraints = applyAll(s.constraints)
+		return s
+	case AlterTable:
+		s.table = apply(s.table)
+		s.actions = applyAll(s.actions)
+		return s
+	case alterAction:
+		s.def = apply(s.def)
+		return s
+	case DropTable:
+		s.table = apply(s.table)
+		return s
+	case CreateIndex:
+		s.table = apply(s.table)
+		s.columns = applyAll(s.columns)
+		s.where = apply(s.where)
+		return s
+	case indexExpr:
+		s.inner = apply(s.inner)
+		return s
+	case DropIndex:
+		s.table = apply(s.table)
+		return s
+	case CreateView:
+		s.name = apply(s.name)
+		s.query = apply(s.query)
+		return s
+	case Delete:
+		s.ctes = applyAll(s.ctes)
+		s.table = apply(s.table)
+		s.sources = mapSources(s.sources, apply)
+		s.where = apply(s.where)
+		s.returning = applyAll(s.returning)
+		return s
+	default:
+		return s
+	}
+}
+
+// Ctes returns the common table expressions of s.
+func (s Select) Ctes() []SQLer { return append([]SQLer{}, s.ctes...) }
+
+// Tables returns the source of s followed by the sources of its joins.
+func (s Select) Tables() []SQLer { return sourceTables(s.queries) }
+
+func sourceTables(queries []query) []SQLer {
+	var list []SQLer
+	for _, q := range queries {
+		if q.table != nil {
+			list = append(list, q.table)
+		}
+	}
+	return list
+}
+
+// Columns returns the selected columns of s and of its joins.
+func (s Select) Columns() []SQLer {
+	var list []SQLer
+	for _, q := range s.queries {
+		list = append(list, q.columns...)
+	}
+	return list
+}
+
+func (s Select) Where() SQLer       { return s.where }
+func (s Select) GroupBy() []SQLer   { return append([]SQLer{}, s.groupby...) }
+func (s Select) Having() SQLer      { return s.having }
+func (s Select) Windows() []SQLer   { return append([]SQLer{}, s.windows...) }
+func (s Select) OrderBy() []SQLer   { return append([]SQLer{}, s.orderby...) }
+func (s Select) Limit() int         { return s.limit }
+func (s Select) Offset() int        { return s.offset }
+func (s Select) Distinct() bool     { return s.distinct }
+func (i Insert) Ctes() []SQLer      { return append([]SQLer{}, i.ctes...) }
+func (i Insert) Table() SQLer       { return i.table }
+func (i Insert) Columns() []SQLer   { return append([]SQLer{}, i.columns...) }
+func (i Insert) Query() SQLer       { return i.query }
+func (i Insert) OnConflict() SQLer  { return i.conflict }
+func (i Insert) Returning() []SQLer { return append([]SQLer{}, i.returning...) }
+
+// Values returns a copy of the rows of values of i.
+func (i Insert) Values() [][]SQLer {
+	values := make([][]SQLer, len(i.values))
+	for j := range i.values {
+		values[j] = append([]SQLer{}, i.values[j]...)
+	}
+	return values
+}
+
+func (u Update) Ctes() []SQLer      { return append([]SQLer{}, u.ctes...) }
+func (u Update) Table() SQLer       { return u.table }
+func (u Update) From() []SQLer      { return sourceTables(u.sources) }
+func (u Update) Set() []SQLer       { return append([]SQLer{}, u.columns...) }
+func (u Update) Where() SQLer       { return u.where }
+func (u Update) Returning() []SQLer { return append([]SQLer{}, u.returning...) }
+func (d Delete) Ctes() []SQLer      { return append([]SQLer{}, d.ctes...) }
+func (d Delete) Table() SQLer       { return d.table }
+func (d Delete) Using() []SQLer     { return sourceTables(d.sources) }
+func (d Delete) Where() SQLer       { return d.where }
+func (d Delete) Returning() []SQLer { return append([]SQLer{}, d.returning...) }
+func (m Merge) Table() SQLer        { return m.table }
+func (m Merge) Source() SQLer       { return m.source }
+func (m Merge) On() SQLer           { return m.cdt }
+func (m Merge) Returning() []SQLer  { return append([]SQLer{}, m.returning...) }
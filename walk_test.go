@@ -0,0 +1,76 @@
+package quel
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestWalk(t *testing.T) {
+	roles, err := NewSelect("roles", SelectColumns("user"), SelectWhere(Equal(NewIdent("name"), Arg("role", "admin"))))
+	if err != nil {
+		t.Fatalf("error creating query! %s", err)
+	}
+	q, err := NewSelect("users", SelectColumns("id", "name"), SelectWhere(In(NewIdent("id"), roles)))
+	if err != nil {
+		t.Fatalf("error creating query! %s", err)
+	}
+	var tables []string
+	Walk(q, VisitFunc(func(s SQLer) bool {
+		if s, ok := s.(Select); ok {
+			for _, t := range s.Tables() {
+				if i, ok := t.(IdentNode); ok {
+					tables = append(tables, i.Name())
+				}
+			}
+		}
+		return true
+	}))
+	if want := []string{"users", "roles"}; !reflect.DeepEqual(tables, want) {
+		t.Errorf("tables mismatched! want %v, got %v", want, tables)
+	}
+
+	var count int
+	Walk(q, VisitFunc(func(s SQLer) bool {
+		if _, ok := s.(IdentNode); ok {
+			count++
+		}
+		_, ok := s.(Select)
+		return !ok
+	}))
+	if count != 0 {
+		t.Errorf("children of select should not have been visited! got %d identifiers", count)
+	}
+}
+
+func TestRewrite(t *testing.T) {
+	q, err := NewSelect("users", SelectColumns("id", "name"), SelectWhere(Equal(NewIdent("active"), Arg("active", true))))
+	if err != nil {
+		t.Fatalf("error creating query! %s", err)
+	}
+	rename := func(s SQLer) SQLer {
+		if i, ok := s.(IdentNode); ok && i.Name() == "users" {
+			return NewIdent("accounts")
+		}
+		return s
+	}
+	compareQueries(t, Rewrite(q, rename), "SELECT id, name FROM accounts WHERE active = ?", []interface{}{true})
+	compareQueries(t, q, "SELECT id, name FROM users WHERE active = ?", []interface{}{true})
+
+	tenant := func(s SQLer) SQLer {
+		sel, ok := s.(Select)
+		if !ok {
+			return s
+		}
+		where := Equal(NewIdent("tenant"), Arg("tenant", 1))
+		if w := sel.Where(); w != nil {
+			where = And(w, where)
+		}
+		sel, err := sel.Apply(SelectWhere(where))
+		if err != nil {
+			t.Fatalf("error applying option! %s", err)
+		}
+		return sel
+	}
+	compareQueries(t, Rewrite(q, tenant), "SELECT id, name FROM users WHERE active = ? AND tenant = ?", []interface{}{true, 1})
+	compareQueries(t, q, "SELECT id, name FROM users WHERE active = ?", []interface{}{true})
+}
@@ -0,0 +1,349 @@
+package quel
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+type WindowOption func(*window) error
+
+// Partition sets the columns of the PARTITION BY clause of a window.
+func Partition(columns ...SQLer) WindowOption {
+	return func(w *window) error {
+		for i := range columns {
+			if columns[i] == nil {
+				return fmt.Errorf("partition: %w", ErrSyntax)
+			}
+		}
+		w.partition = append(w.partition, columns...)
+		return nil
+	}
+}
+
+// Order sets the ORDER BY clause of a window. by can be made of columns,
+// expressions or of the result of Asc and Desc.
+func Order(by ...SQLer) WindowOption {
+	return func(w *window) error {
+		for i := range by {
+			if by[i] == nil {
+				return fmt.Errorf("order: %w", ErrSyntax)
+			}
+		}
+		w.orderby = append(w.orderby, by...)
+		return nil
+	}
+}
+
+// WindowRef makes a window refer to the window name defined with
+// SelectWindow. The other options of the window then extend the referenced
+// window.
+func WindowRef(name string) WindowOption {
+	return func(w *window) error {
+		if !isValidIdentifier(name) {
+			return fmt.Errorf("window: %w %q", ErrIdent, name)
+		}
+		w.ref = name
+		return nil
+	}
+}
+
+// Rows sets a frame clause in ROWS mode. bounds is made of one or two of the
+// values returned by Preceding, Following, UnboundedPreceding,
+// UnboundedFollowing and CurrentRow.
+func Rows(bounds ...SQLer) WindowOption {
+	return withFrame("ROWS", bounds)
+}
+
+// Range sets a frame clause in RANGE mode.
+func Range(bounds ...SQLer) WindowOption {
+	return withFrame("RANGE", bounds)
+}
+
+// Groups sets a frame clause in GROUPS mode.
+func Groups(bounds ...SQLer) WindowOption {
+	return withFrame("GROUPS", bounds)
+}
+
+func withFrame(mode string, bounds []SQLer) WindowOption {
+	return func(w *window) error {
+		if len(bounds) == 0 || len(bounds) > 2 {
+			return fmt.Errorf("%s: %w: expected 1 or 2 bounds, got %d", strings.ToLower(mode), ErrSyntax, len(bounds))
+		}
+		for i := range bounds {
+			if _, ok := bounds[i].(bound); !ok {
+				return fmt.Errorf("%s: %w: invalid bound", strings.ToLower(mode), ErrSyntax)
+			}
+		}
+		w.frame = mode
+		w.bounds = append([]SQLer{}, bounds...)
+		return nil
+	}
+}
+
+type window struct {
+	fn        SQLer
+	ref       string
+	partition []SQLer
+	orderby   []SQLer
+	frame     string
+	bounds    []SQLer
+	err       error
+}
+
+// Over turns fn into a window function, eg:
+//
+//	Over(RowNumber(), Partition(NewIdent("user")), Order(Desc("created")))
+//
+// Errors returned by the options are reported when the query is rendered.
+func Over(fn SQLer, options ...WindowOption) SQLer {
+	w := window{
+		fn: fn,
+	}
+	if _, ok := fn.(function); !ok {
+		w.err = fmt.Errorf("over: %w: function expected", ErrSyntax)
+		return w
+	}
+	for _, opt := range options {
+		if w.err = opt(&w); w.err != nil {
+			break
+		}
+	}
+	return w
+}
+
+// Window returns a window specification to be given to SelectWindow.
+func Window(options ...WindowOption) SQLer {
+	var w window
+	for _, opt := range options {
+		if w.err = opt(&w); w.err != nil {
+			break
+		}
+	}
+	return w
+}
+
+func (w window) Alias(name string) SQLer {
+	return Alias(name, w)
+}
+
+func (w window) SQL() (string, []interface{}, error) {
+	return SQLDialect(w, Default)
+}
+
+func (w window) render(r *renderer) (string, []interface{}, error) {
+	if w.err != nil {
+		return "", nil, w.err
+	}
+	if w.fn == nil {
+		spec, args, err := w.renderSpec(r)
+		if err != nil {
+			return "", nil, err
+		}
+		return fmt.Sprintf("(%s)", spec), args, nil
+	}
+	fn, args, err := r.render(w.fn)
+	if err != nil {
+		return "", nil, err
+	}
+	if w.ref != "" && len(w.partition) == 0 && len(w.orderby) == 0 && w.frame == "" {
+		return fmt.Sprintf("%s %s %s", fn, r.keyword("OVER"), r.ident(w.ref)), args, nil
+	}
+	spec, as, err := w.renderSpec(r)
+	if err != nil {
+		return "", nil, err
+	}
+	args = append(args, as...)
+	return fmt.Sprintf("%s %s (%s)", fn, r.keyword("OVER"), spec), args, nil
+}
+
+func (w window) renderSpec(r *renderer) (string, []interface{}, error) {
+	var (
+		parts []string
+		args  []interface{}
+	)
+	if w.ref != "" {
+		parts = append(parts, r.ident(w.ref))
+	}
+	if len(w.partition) > 0 {
+		var b strings.Builder
+		b.WriteString(r.keyword("PARTITION BY") + " ")
+		as, err := writeSQL(r, &b, w.partition...)
+		if err != nil {
+			return "", nil, err
+		}
+		args = append(args, as...)
+		parts = append(parts, b.String())
+	}
+	if len(w.orderby) > 0 {
+		var b strings.Builder
+		b.WriteString(r.keyword("ORDER BY") + " ")
+		as, err := writeSQL(r, &b, w.orderby...)
+		if err != nil {
+			return "", nil, err
+		}
+		args = append(args, as...)
+		parts = append(parts, b.String())
+	}
+	if w.frame != "" {
+		var list []string
+		for _, b := range w.bounds {
+			str, _, err := r.render(b)
+			if err != nil {
+				return "", nil, err
+			}
+			list = append(list, str)
+		}
+		str := r.keyword(w.frame) + " " + list[0]
+		if len(list) == 2 {
+			str = fmt.Sprintf("%s %s %s %s %s", r.keyword(w.frame), r.keyword("BETWEEN"), list[0], r.keyword("AND"), list[1])
+		}
+		parts = append(parts, str)
+	}
+	return strings.Join(parts, " "), args, nil
+}
+
+type namedWindow struct {
+	name string
+	spec SQLer
+}
+
+func (n namedWindow) SQL() (string, []interface{}, error) {
+	return SQLDialect(n, Default)
+}
+
+func (n namedWindow) render(r *renderer) (string, []interface{}, error) {
+	spec, args, err := r.render(n.spec)
+	if err != nil {
+		return "", nil, err
+	}
+	return fmt.Sprintf("%s %s %s", r.ident(n.name), r.keyword("AS"), spec), args, nil
+}
+
+// checkWindows verifies that the windows used by the columns, the ORDER BY
+// clause and the WINDOW clause of s have been defined with SelectWindow.
+func checkWindows(s Select) error {
+	var (
+		names = make(map[string]struct{})
+		err   error
+	)
+	check := func(ref string) {
+		if _, ok := names[ref]; ref != "" && !ok && err == nil {
+			err = fmt.Errorf("window: %w %q: not defined", ErrIdent, ref)
+		}
+	}
+	for _, w := range s.windows {
+		n, ok := w.(namedWindow)
+		if !ok {
+			continue
+		}
+		if spec, ok := n.spec.(window); ok {
+			check(spec.ref)
+		}
+		names[n.name] = struct{}{}
+	}
+	visit := VisitFunc(func(n SQLer) bool {
+		if w, ok := n.(window); ok {
+			check(w.ref)
+		}
+		return !isStatement(n)
+	})
+	for _, c := range s.Columns() {
+		Walk(c, visit)
+	}
+	for _, o := range s.orderby {
+		Walk(o, visit)
+	}
+	return err
+}
+
+const (
+	preceding uint8 = iota
+	following
+	unboundedPreceding
+	unboundedFollowing
+	currentRow
+)
+
+type bound struct {
+	kind   uint8
+	offset int
+}
+
+// Preceding returns the bound of a frame starting n rows before the current
+// one.
+func Preceding(n int) SQLer {
+	return bound{kind: preceding, offset: n}
+}
+
+// Following returns the bound of a frame ending n rows after the current one.
+func Following(n int) SQLer {
+	return bound{kind: following, offset: n}
+}
+
+func UnboundedPreceding() SQLer {
+	return bound{kind: unboundedPreceding}
+}
+
+func UnboundedFollowing() SQLer {
+	return bound{kind: unboundedFollowing}
+}
+
+func CurrentRow() SQLer {
+	return bound{kind: currentRow}
+}
+
+func (b bound) SQL() (string, []interface{}, error) {
+	return SQLDialect(b, Default)
+}
+
+func (b bound) render(r *renderer) (string, []interface{}, error) {
+	var str string
+	switch b.kind {
+	case preceding, following:
+		if b.offset < 0 {
+			return "", nil, fmt.Errorf("frame: %w: %d", ErrLimit, b.offset)
+		}
+		str = strconv.Itoa(b.offset) + " PRECEDING"
+		if b.kind == following {
+			str = strconv.Itoa(b.offset) + " FOLLOWING"
+		}
+	case unboundedPreceding:
+		str = "UNBOUNDED PRECEDING"
+	case unboundedFollowing:
+		str = "UNBOUNDED FOLLOWING"
+	case currentRow:
+		str = "CURRENT ROW"
+	default:
+		return "", nil, fmt.Errorf("unsupported frame bound")
+	}
+	return r.keyword(str), nil, nil
+}
+
+func RowNumber() SQLer {
+	return Func("ROW_NUMBER")
+}
+
+func Rank() SQLer {
+	return Func("RANK")
+}
+
+func DenseRank() SQLer {
+	return Func("DENSE_RANK")
+}
+
+func Lag(value SQLer, offset int) SQLer {
+	return Func("LAG", value, NewLiteral(offset))
+}
+
+func Lead(value SQLer, offset int) SQLer {
+	return Func("LEAD", value, NewLiteral(offset))
+}
+
+func FirstValue(value SQLer) SQLer {
+	return Func("FIRST_VALUE", value)
+}
+
+func NTile(n int) SQLer {
+	return Func("NTILE", NewLiteral(n))
+}